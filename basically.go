@@ -1,5 +1,7 @@
 package basically
 
+import "time"
+
 // A Document represents a given text, and is responsible for
 // handling the summarization and keyword extraction process.
 type Document interface {
@@ -23,6 +25,15 @@ type Summarizer interface {
 	Rank(iters int)
 }
 
+// An IncrementalSummarizer is a Summarizer which can insert and evict sentences without
+// rebuilding its graph, for summarizing text as it arrives.
+type IncrementalSummarizer interface {
+	Summarizer
+	Insert(sents []*Sentence, similar Similarity, filter TokenFilter,
+		focusString *Sentence, threshold float64)
+	Evict(n int)
+}
+
 // A Highlighter is responsible for extracting key words from a document.
 type Highlighter interface {
 	Initialize(tokens []*Token, filter TokenFilter, window int)
@@ -39,9 +50,20 @@ type Similarity func(n1, n2 []*Token, filter TokenFilter) float64
 // A Token represents an individual token of text such as a word or punctuation
 // symbol.
 type Token struct {
-	Tag   string // The token's part-of-speech tag.
-	Text  string // The token's actual content.
-	Order int    // The token's order in the text.
+	Tag      string // The token's part-of-speech tag.
+	Text     string // The token's actual content.
+	Label    string // The token's IOB label (if entities are extracted).
+	Entity   string // The token's named-entity type (if any).
+	Order    int    // The token's order in the text.
+	Sentence int    // The order of the sentence containing the token.
+}
+
+// An Entity represents a named-entity, such as a person or organization,
+// spanning one or more tokens.
+type Entity struct {
+	Text   string   // The entity's actual content.
+	Label  string   // The entity's type.
+	Tokens []*Token // The tokens making up the entity.
 }
 
 // A Keyword is the keyword belonging to a highlighted document.
@@ -53,10 +75,59 @@ type Keyword struct {
 
 // A Sentence represents an individual sentence within the text.
 type Sentence struct {
-	Raw       string   // Raw sentence string.
-	Tokens    []*Token // Tokenized sentence.
-	Sentiment float64  // Sentiment score.
-	Score     float64  // Score (weight) of the sentence.
-	Bias      float64  // Bias assigned to the sentence for ranking.
-	Order     int      // The sentence's order in the text.
+	Raw       string        // Raw sentence string.
+	Tokens    []*Token      // Tokenized sentence.
+	Entities  []*Entity     // Named-entities within the sentence.
+	Sentiment float64       // Sentiment score.
+	Score     float64       // Score (weight) of the sentence.
+	Bias      float64       // Bias assigned to the sentence for ranking.
+	Order     int           // The sentence's order in the text.
+	Paragraph int           // The order of the paragraph containing the sentence.
+	Section   int           // The order of the section (started by a heading) containing the sentence.
+	Kind      Kind          // The sentence's structural role, such as a heading or list item.
+	Level     int           // The heading level (1 for top-level headings), or 0 if not a heading.
+	Start     int           // The byte offset of the sentence in the original text.
+	End       int           // The byte offset following the sentence in the original text.
+	StartTime time.Duration // The start time of the sentence (in timed transcripts).
+	EndTime   time.Duration // The end time of the sentence (in timed transcripts).
+	Speaker   string        // The speaker of the sentence (in transcripts), or its sender (in emails).
+	Date      time.Time     // The date of the sentence (in emails).
+	Message   int           // The order of the message containing the sentence (in emails).
+	Source    string        // The name of the document containing the sentence (in multi-document summaries).
+}
+
+// A Kind describes the structural role of a sentence within the text.
+type Kind int
+
+const (
+	Body    Kind = iota // Sentence within a paragraph.
+	Heading             // Heading starting a new section.
+	Bullet              // Sentence within a list item.
+)
+
+func (k Kind) String() string {
+	switch k {
+	case Heading:
+		return "heading"
+	case Bullet:
+		return "bullet"
+	}
+	return "body"
+}
+
+// A Unit measures the length of a summary.
+type Unit int
+
+const (
+	Sentences  Unit = iota // Number of sentences.
+	Words                  // Number of words (separated by whitespace).
+	Characters             // Number of characters.
+	Tokens                 // Number of tokens.
+	Seconds                // Estimated reading time, in seconds.
+)
+
+// A Budget limits the length of a summary, in some unit.
+type Budget struct {
+	Unit  Unit // The unit of the limit.
+	Limit int  // The maximum length of the summary.
 }
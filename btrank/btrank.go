@@ -15,32 +15,59 @@ type BiasedTextRank struct {
 	Graph *SGraph
 }
 
-var _ basically.Summarizer = (*BiasedTextRank)(nil)
+var _ basically.IncrementalSummarizer = (*BiasedTextRank)(nil)
 
 // Initialize initializes the underlying SGraph by inserting nodes and edges.
 func (btr *BiasedTextRank) Initialize(sents []*basically.Sentence, similar basically.Similarity,
 	filter basically.TokenFilter, focusString *basically.Sentence, threshold float64) {
 	// Instantiate a new SGraph with the appropriate nodes and edges.
-	btr.Graph = &SGraph{Nodes: sents, Edges: make([][]float64, len(sents))}
+	btr.Graph = &SGraph{
+		Nodes: make([]*basically.Sentence, 0, len(sents)),
+		Edges: make([][]float64, 0, len(sents)),
+	}
+	btr.Insert(sents, similar, filter, focusString, threshold)
+}
+
+// Insert inserts the sentences as nodes into the SGraph, only constructing the edges of the new
+// nodes, so that sentences can be added as they arrive.
+func (btr *BiasedTextRank) Insert(sents []*basically.Sentence, similar basically.Similarity,
+	filter basically.TokenFilter, focusString *basically.Sentence, threshold float64) {
+	if btr.Graph == nil {
+		btr.Graph = &SGraph{}
+	}
 
-	// Updates the bias value of each node (sentence) if necessary, and
-	// constructs the edge slice for each node.
-	for idx, sent := range sents {
+	for _, sent := range sents {
+		// Updates the bias value of the node (sentence) if necessary.
 		if focusString != nil {
-			btr.Graph.Nodes[idx].Bias = similar(focusString.Tokens, sent.Tokens, filter)
+			sent.Bias = similar(focusString.Tokens, sent.Tokens, filter)
 		}
-		btr.Graph.Edges[idx] = make([]float64, idx+1)
-	}
 
-	// Constructs the edges between nodes satisfying the threshold,
-	// using the given similarity function, and token filter.
-	for i := 0; i < len(btr.Graph.Nodes); i++ {
-		for j := 0; j < i; j++ {
-			sim := similar(btr.Graph.Nodes[i].Tokens, btr.Graph.Nodes[j].Tokens, filter)
+		// Constructs the edges to previous nodes satisfying the threshold,
+		// using the given similarity function, and token filter.
+		idx := len(btr.Graph.Nodes)
+		edges := make([]float64, idx+1)
+		for j := 0; j < idx; j++ {
+			sim := similar(sent.Tokens, btr.Graph.Nodes[j].Tokens, filter)
 			if sim > threshold {
-				btr.Graph.Edges[i][j] = sim
+				edges[j] = sim
 			}
 		}
+
+		btr.Graph.Nodes = append(btr.Graph.Nodes, sent)
+		btr.Graph.Edges = append(btr.Graph.Edges, edges)
+	}
+}
+
+// Evict removes the n oldest nodes (and their edges) from the SGraph.
+func (btr *BiasedTextRank) Evict(n int) {
+	if n > len(btr.Graph.Nodes) {
+		n = len(btr.Graph.Nodes)
+	}
+
+	btr.Graph.Nodes = btr.Graph.Nodes[n:]
+	btr.Graph.Edges = btr.Graph.Edges[n:]
+	for idx := range btr.Graph.Edges {
+		btr.Graph.Edges[idx] = btr.Graph.Edges[idx][n:]
 	}
 }
 
@@ -69,6 +96,8 @@ func (btr *BiasedTextRank) outWeights() []float64 {
 }
 
 // Rank applies the Biased TextRank algorithm on the SGraph for some specified iterations.
+// Ranking starts from the current scores, so that few iterations are needed after inserting
+// or evicting nodes.
 func (btr *BiasedTextRank) Rank(iters int) {
 	n := len(btr.Graph.Nodes)
 	outWeights := btr.outWeights()
@@ -1,62 +1,441 @@
-package main
-
-import (
-	"fmt"
-	"log"
-	"os"
-	"strconv"
-	"time"
-
-	"github.com/algao1/basically/btrank"
-	"github.com/algao1/basically/document"
-	"github.com/algao1/basically/parser"
-	"github.com/algao1/basically/trank"
-)
-
-func main() {
-	start := time.Now()
-
-	sumlen, _ := strconv.Atoi(os.Args[1])
-	files := os.Args[2:]
-
-	p := parser.Create()
-	s := &btrank.BiasedTextRank{}
-	kwtr := &trank.KWTextRank{}
-
-	for _, file := range files {
-		data, err := os.ReadFile(file)
-		if err != nil {
-			log.Fatal(err)
-		}
-
-		doc, err := document.Create(string(data), s, kwtr, p)
-		if err != nil {
-			log.Fatal(err)
-		}
-
-		sums, err := doc.Summarize(sumlen, 0.3, "")
-		if err != nil {
-			log.Fatal(err)
-		}
-
-		orig, redu := doc.Characters()
-		fmt.Println("Reduced by:", (1-float64(redu)/float64(orig))*100)
-
-		for _, sum := range sums {
-			fmt.Printf("[%.2f, %.2f]\n", sum.Score, sum.Sentiment)
-			fmt.Println(sum.Raw)
-		}
-
-		kws, err := doc.Highlight(20, true)
-		if err != nil {
-			log.Fatal(err)
-		}
-
-		for _, kw := range kws {
-			fmt.Println(kw.Weight, kw.Word)
-		}
-	}
-
-	elapsed := time.Since(start)
-	log.Printf("Function took %s", elapsed)
-}
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/algao1/basically"
+	"github.com/algao1/basically/btrank"
+	"github.com/algao1/basically/document"
+	"github.com/algao1/basically/document/sentence"
+	"github.com/algao1/basically/parser"
+	"github.com/algao1/basically/trank"
+)
+
+func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "punkt":
+			trainPunkt(os.Args[2:])
+			return
+		case "tagger":
+			trainTagger(os.Args[2:])
+			return
+		case "ner":
+			trainEntities(os.Args[2:])
+			return
+		}
+	}
+	summarize(os.Args[1:])
+}
+
+// summarize summarizes and highlights each of the given files, or all of them together.
+// Usage: basically [flags] <length> <files...>
+func summarize(args []string) {
+	fs := flag.NewFlagSet("basically", flag.ExitOnError)
+	punkt := fs.String("punkt", "", "path to a custom Punkt model")
+	tagger := fs.String("tagger", "", "path to a custom POS tagger")
+	ner := fs.String("ner", "", "path to a custom entity model")
+	format := fs.String("format", "text", "input format (text, markdown, html, subtitles, transcript or email)")
+	speaker := fs.String("speaker", "", "speaker to focus the summary on (in transcripts)")
+	balance := fs.Bool("balance", false, "balance the summary across speakers (in transcripts)")
+	messages := fs.Bool("messages", false, "summarize each message separately (in emails)")
+	multi := fs.Bool("multi", false, "summarize all files together, as related documents")
+	chunk := fs.Int("chunk", 0, "summarize hierarchically, in chunks of this many sentences (for long documents)")
+	topics := fs.Int("topics", 0, "split sections by topic, comparing blocks of this many sentences (for texts without headings)")
+	sections := fs.Bool("sections", false, "summarize and highlight each section separately")
+	budget := fs.String("budget", "", "limit the summary to a budget instead of a length (e.g. words:100, chars:600, tokens:150 or seconds:30)")
+	compress := fs.Bool("compress", false, "compress summary sentences, removing parentheticals, appositives and attributions")
+	compare := fs.Bool("compare", false, "compare two files, instead of summarizing them")
+	seen := fs.String("seen", "", "comma-separated files already seen, to summarize only what is new")
+	fs.Parse(args)
+
+	if fs.NArg() < 2 {
+		log.Fatal("usage: basically [flags] <length> <files...>")
+	}
+
+	start := time.Now()
+
+	sumlen, _ := strconv.Atoi(fs.Arg(0))
+	files := fs.Args()[1:]
+
+	var cfgs []parser.Config
+	if *punkt != "" {
+		model, err := parser.PunktFromDisk(*punkt)
+		if err != nil {
+			log.Fatal(err)
+		}
+		cfgs = append(cfgs, parser.WithPunktModel(model))
+	}
+	if *tagger != "" {
+		model, err := parser.TaggerFromDisk(*tagger)
+		if err != nil {
+			log.Fatal(err)
+		}
+		cfgs = append(cfgs, parser.WithTagger(model))
+	}
+	if *ner != "" {
+		model, err := parser.EntitiesFromDisk(*ner)
+		if err != nil {
+			log.Fatal(err)
+		}
+		cfgs = append(cfgs, parser.WithEntityModel(model))
+	}
+	switch *format {
+	case "text":
+	case "markdown", "md":
+		cfgs = append(cfgs, parser.WithMarkdown())
+	case "html":
+		cfgs = append(cfgs, parser.WithHTML())
+	case "subtitles", "srt", "vtt":
+		cfgs = append(cfgs, parser.WithSubtitles())
+	case "transcript":
+		cfgs = append(cfgs, parser.WithTranscript())
+	case "email", "mbox":
+		cfgs = append(cfgs, parser.WithEmail())
+	default:
+		log.Fatalf("unknown format %q", *format)
+	}
+
+	var dcfgs []document.Config
+	if *speaker != "" {
+		dcfgs = append(dcfgs, document.WithSpeakerFocus())
+	}
+	if *balance {
+		dcfgs = append(dcfgs, document.WithSpeakerBalance())
+	}
+	if *chunk > 0 {
+		dcfgs = append(dcfgs, document.WithHierarchy(*chunk, func() basically.Summarizer {
+			return &btrank.BiasedTextRank{}
+		}))
+	}
+	if *compress {
+		dcfgs = append(dcfgs, document.WithCompression(sentence.CreateCompressor()))
+	}
+	if *topics > 0 {
+		dcfgs = append(dcfgs, document.WithTopics(*topics))
+	}
+	if *seen != "" {
+		var previous []string
+		for _, file := range strings.Split(*seen, ",") {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				log.Fatal(err)
+			}
+			previous = append(previous, string(data))
+		}
+		dcfgs = append(dcfgs, document.WithPrevious(previous...))
+	}
+
+	var limit *basically.Budget
+	if *budget != "" {
+		b, err := parseBudget(*budget)
+		if err != nil {
+			log.Fatal(err)
+		}
+		limit = &b
+	}
+
+	p := parser.Create(cfgs...)
+	s := &btrank.BiasedTextRank{}
+	kwtr := &trank.KWTextRank{}
+
+	texts := make([]string, 0, len(files))
+	names := make([]string, 0, len(files))
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			log.Fatal(err)
+		}
+		texts = append(texts, string(data))
+		names = append(names, filepath.Base(file))
+	}
+
+	var docs []*document.Document
+	if *multi {
+		doc, err := document.CreateMulti(texts, names, s, kwtr, p, dcfgs...)
+		if err != nil {
+			log.Fatal(err)
+		}
+		docs = append(docs, doc)
+	} else {
+		for _, text := range texts {
+			doc, err := document.Create(text, s, kwtr, p, dcfgs...)
+			if err != nil {
+				log.Fatal(err)
+			}
+			docs = append(docs, doc.(*document.Document))
+		}
+	}
+
+	if *compare {
+		if len(docs) != 2 {
+			log.Fatal("-compare requires exactly two files")
+		}
+		cmp, err := document.Compare(docs[0], docs[1], 0.5)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		for _, al := range cmp.Shared {
+			fmt.Printf("= [%.2f] %s\n  [%.2f] %s\n", al.Score, al.Sentence.Raw, al.Score, al.Match.Raw)
+		}
+		for _, al := range cmp.OnlyA {
+			fmt.Printf("- [%.2f] %s\n", al.Score, al.Sentence.Raw)
+		}
+		for _, al := range cmp.OnlyB {
+			fmt.Printf("+ [%.2f] %s\n", al.Score, al.Sentence.Raw)
+		}
+		return
+	}
+
+	for _, doc := range docs {
+		var err error
+		var sums []*basically.Sentence
+		if limit != nil {
+			sums, err = doc.SummarizeBudget(*limit, *speaker)
+			if err != nil {
+				log.Fatal(err)
+			}
+		} else if *sections {
+			secs, err := doc.SummarizeSections(sumlen, 0.3)
+			if err != nil {
+				log.Fatal(err)
+			}
+			for _, sec := range secs {
+				sums = append(sums, sec...)
+			}
+		} else if *messages {
+			msgs, err := doc.SummarizeMessages(sumlen, 0.3)
+			if err != nil {
+				log.Fatal(err)
+			}
+			for _, msg := range msgs {
+				sums = append(sums, msg...)
+			}
+		} else {
+			sums, err = doc.Summarize(sumlen, 0.3, *speaker)
+			if err != nil {
+				log.Fatal(err)
+			}
+		}
+
+		orig, redu := doc.Characters()
+		fmt.Println("Reduced by:", (1-float64(redu)/float64(orig))*100)
+
+		for _, sum := range sums {
+			fmt.Printf("[%.2f, %.2f]\n", sum.Score, sum.Sentiment)
+			if sum.EndTime > 0 {
+				fmt.Printf("[%s - %s]\n", clock(sum.StartTime), clock(sum.EndTime))
+			}
+			if !sum.Date.IsZero() {
+				fmt.Printf("[%s] ", sum.Date.Format("2006-01-02 15:04"))
+			}
+			if sum.Speaker != "" {
+				fmt.Printf("%s: ", sum.Speaker)
+			}
+			fmt.Print(sum.Raw)
+			if sum.Source != "" {
+				fmt.Printf(" (%s)", sum.Source)
+			}
+			fmt.Println()
+		}
+
+		kws, err := doc.Highlight(20, true)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		for _, kw := range kws {
+			fmt.Println(kw.Weight, kw.Word)
+		}
+
+		if *sections {
+			secs, err := doc.HighlightSections(5, true)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			for idx, kws := range secs {
+				fmt.Printf("Section %d:", idx+1)
+				for _, kw := range kws {
+					fmt.Printf(" %s", kw.Word)
+				}
+				fmt.Println()
+			}
+		}
+
+		for _, name := range doc.Speakers() {
+			kws, err := doc.HighlightSpeaker(name, 5, true)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			fmt.Printf("%s:", name)
+			for _, kw := range kws {
+				fmt.Printf(" %s", kw.Word)
+			}
+			fmt.Println()
+		}
+	}
+
+	elapsed := time.Since(start)
+	log.Printf("Function took %s", elapsed)
+}
+
+// parseBudget parses a budget of the form unit:limit, such as words:100.
+func parseBudget(s string) (basically.Budget, error) {
+	units := map[string]basically.Unit{
+		"sentences": basically.Sentences,
+		"words":     basically.Words,
+		"chars":     basically.Characters,
+		"tokens":    basically.Tokens,
+		"seconds":   basically.Seconds,
+	}
+
+	parts := strings.SplitN(s, ":", 2)
+	if len(parts) != 2 {
+		return basically.Budget{}, fmt.Errorf("invalid budget %q", s)
+	}
+	unit, ok := units[parts[0]]
+	limit, err := strconv.Atoi(parts[1])
+	if !ok || err != nil {
+		return basically.Budget{}, fmt.Errorf("invalid budget %q", s)
+	}
+	return basically.Budget{Unit: unit, Limit: limit}, nil
+}
+
+// clock formats a duration as a timestamp (hh:mm:ss).
+func clock(d time.Duration) string {
+	d = d.Round(time.Second)
+	return fmt.Sprintf("%02d:%02d:%02d", int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60)
+}
+
+// trainPunkt trains a Punkt model on the given plain-text corpus, and saves it to disk.
+// Usage: basically punkt [-o punkt.json] <files...>
+func trainPunkt(args []string) {
+	fs := flag.NewFlagSet("punkt", flag.ExitOnError)
+	out := fs.String("o", "punkt.json", "output path of the trained model")
+	fs.Parse(args)
+
+	if fs.NArg() < 1 {
+		log.Fatal("usage: basically punkt [-o punkt.json] <files...>")
+	}
+
+	corpus := make([]string, 0, fs.NArg())
+	for _, file := range fs.Args() {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			log.Fatal(err)
+		}
+		corpus = append(corpus, string(data))
+	}
+
+	model, err := parser.TrainPunkt(corpus...)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := model.Write(*out); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Saved Punkt model with %d abbreviations to %s\n", len(model.Abbreviations()), *out)
+}
+
+// trainTagger trains a POS tagger on the given CoNLL or slash-tagged files, and saves it to disk.
+// Usage: basically tagger [-sep /] [-iters 5] [-o tagger] <files...>
+func trainTagger(args []string) {
+	fs := flag.NewFlagSet("tagger", flag.ExitOnError)
+	sep := fs.String("sep", "", "tag separator for slash-tagged files (CoNLL if empty)")
+	iters := fs.Int("iters", 5, "number of training iterations")
+	out := fs.String("o", "tagger", "output directory of the trained model")
+	fs.Parse(args)
+
+	if fs.NArg() < 1 {
+		log.Fatal("usage: basically tagger [-sep /] [-iters 5] [-o tagger] <files...>")
+	}
+
+	var sents []parser.TaggedSentence
+	for _, file := range fs.Args() {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		if *sep != "" {
+			sents = append(sents, parser.ReadTagged(string(data), *sep)...)
+		} else {
+			sents = append(sents, parser.ReadCoNLL(string(data))...)
+		}
+	}
+
+	model, err := parser.TrainTagger(sents, *iters)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := model.Write(*out); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Saved tagger trained on %d sentences (accuracy %.3f) to %s\n",
+		len(sents), model.Accuracy(sents), *out)
+}
+
+// trainEntities trains an entity model on Prodigy-style JSONL annotations, and saves it to disk.
+// The model is optionally evaluated on held-out annotations.
+// Usage: basically ner [-o ner] [-eval heldout.jsonl] <files...>
+func trainEntities(args []string) {
+	fs := flag.NewFlagSet("ner", flag.ExitOnError)
+	out := fs.String("o", "ner", "output directory of the trained model")
+	eval := fs.String("eval", "", "held-out annotations to evaluate the model on")
+	fs.Parse(args)
+
+	if fs.NArg() < 1 {
+		log.Fatal("usage: basically ner [-o ner] [-eval heldout.jsonl] <files...>")
+	}
+
+	var annots []parser.EntityAnnotation
+	for _, file := range fs.Args() {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		entries, err := parser.ReadProdigy(data)
+		if err != nil {
+			log.Fatalf("%s: %s", file, err)
+		}
+		annots = append(annots, entries...)
+	}
+
+	model := parser.TrainEntities(filepath.Base(*out), annots)
+	if err := model.Write(*out); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Saved entity model trained on %d annotations to %s\n", len(annots), *out)
+
+	if *eval == "" {
+		return
+	}
+
+	data, err := os.ReadFile(*eval)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	heldout, err := parser.ReadProdigy(data)
+	if err != nil {
+		log.Fatalf("%s: %s", *eval, err)
+	}
+
+	fmt.Printf("%-16s %9s %9s %9s\n", "label", "precision", "recall", "support")
+	for _, score := range model.Evaluate(heldout) {
+		fmt.Printf("%-16s %9.3f %9.3f %9d\n", score.Label, score.Precision, score.Recall, score.Support)
+	}
+}
@@ -0,0 +1,127 @@
+package document
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/algao1/basically"
+)
+
+const (
+	// The average silent reading speed of English non-fiction, in words per minute.
+	// See Brysbaert (2019), "How many words do we read per minute?".
+	readingSpeed = 238
+	// The number of top-ranked sentences considered for a budgeted summary.
+	budgetCandidates = 200
+)
+
+// SummarizeBudget returns a summary filling the budget with the highest ranked sentences that fit
+// within it, focusing on the focus string (see Summarize). Rather than cutting the ranking short,
+// the sentences are selected as a knapsack, so that a long sentence overflowing the budget may
+// give way to several shorter ones of greater total score.
+func (doc *Document) SummarizeBudget(budget basically.Budget, raw string) ([]*basically.Sentence, error) {
+	if budget.Limit <= 0 {
+		return nil, fmt.Errorf("invalid budget of %d", budget.Limit)
+	}
+	switch budget.Unit {
+	case basically.Sentences, basically.Words, basically.Characters, basically.Tokens, basically.Seconds:
+	default:
+		return nil, fmt.Errorf("unknown budget unit %d", budget.Unit)
+	}
+
+	sents, err := doc.rank(len(doc.Sentences), raw)
+	if err != nil {
+		return nil, err
+	}
+
+	// Removes sentences redundant with higher-ranked ones.
+	length := len(sents)
+	if doc.Configs.redundancy > 0 {
+		length = doc.removeRedundant(sents, length)
+	}
+	if length > budgetCandidates {
+		length = budgetCandidates
+	}
+	sents = sents[:length]
+
+	if budget.Unit == basically.Sentences {
+		if budget.Limit < len(sents) {
+			sents = sents[:budget.Limit]
+		}
+		return doc.finish(sents), nil
+	}
+
+	// Reading time is budgeted as the number of words read in that time.
+	limit, unit := budget.Limit, budget.Unit
+	if unit == basically.Seconds {
+		limit, unit = budget.Limit*readingSpeed/60, basically.Words
+	}
+	sents, err = knapsack(sents, limit, unit)
+	if err != nil {
+		return nil, err
+	}
+	return doc.finish(sents), nil
+}
+
+// knapsack selects the sentences of greatest total score whose total cost (in the given unit)
+// is within the limit, in order of score.
+func knapsack(sents []*basically.Sentence, limit int, unit basically.Unit) ([]*basically.Sentence, error) {
+	costs := make([]int, len(sents))
+	var total int
+	for idx, sent := range sents {
+		c, err := cost(sent, unit)
+		if err != nil {
+			return nil, err
+		}
+		costs[idx] = c
+		total += c
+	}
+	if total <= limit {
+		return sents, nil
+	}
+
+	// best[c] is the greatest score of the sentences considered so far costing at most c,
+	// and taken[i][c] records whether the i-th sentence is part of it.
+	best := make([]float64, limit+1)
+	taken := make([][]bool, len(sents))
+	for idx, sent := range sents {
+		taken[idx] = make([]bool, limit+1)
+		for c := limit; c >= costs[idx]; c-- {
+			if score := best[c-costs[idx]] + sent.Score; score > best[c] {
+				best[c] = score
+				taken[idx][c] = true
+			}
+		}
+	}
+
+	// Backtracks to recover the selected sentences.
+	selected := make([]bool, len(sents))
+	for idx, c := len(sents)-1, limit; idx >= 0; idx-- {
+		if taken[idx][c] {
+			selected[idx] = true
+			c -= costs[idx]
+		}
+	}
+
+	ret := make([]*basically.Sentence, 0)
+	for idx, sent := range sents {
+		if selected[idx] {
+			ret = append(ret, sent)
+		}
+	}
+	return ret, nil
+}
+
+// cost returns the length of the sentence in the given unit (words, characters or tokens).
+func cost(sent *basically.Sentence, unit basically.Unit) (int, error) {
+	switch unit {
+	case basically.Words:
+		return len(strings.Fields(sent.Raw)), nil
+	case basically.Characters:
+		return utf8.RuneCountInString(sent.Raw), nil
+	case basically.Tokens:
+		return len(sent.Tokens), nil
+	}
+	return 0, fmt.Errorf("unknown budget unit %d", unit)
+}
@@ -0,0 +1,81 @@
+package document
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/algao1/basically"
+)
+
+// An Alignment pairs a sentence with the most similar sentence of another document.
+type Alignment struct {
+	Sentence *basically.Sentence // The aligned sentence.
+	Match    *basically.Sentence // The most similar sentence of the other document (if any).
+	Score    float64             // The overlap of the sentences, from 0 (unrelated) to 1 (duplicates).
+}
+
+// A Comparison contains the sentences shared by two documents, and those distinctive to each.
+type Comparison struct {
+	Shared []*Alignment // Sentences of the first document, matched in the second.
+	OnlyA  []*Alignment // Sentences distinctive to the first document.
+	OnlyB  []*Alignment // Sentences distinctive to the second document.
+}
+
+// Compare compares two documents (such as two versions of a policy, or two outlets covering the
+// same event), aligning every sentence with its most similar sentence in the other document using
+// the first document's similarity and filter. Sentences whose overlap (see WithRedundancy) is at
+// least the threshold are shared, while the rest are distinctive. Sentences are in order of
+// appearance.
+func Compare(a, b basically.Document, threshold float64) (*Comparison, error) {
+	docA, ok := a.(*Document)
+	if !ok {
+		return nil, fmt.Errorf("unable to compare documents of type %T", a)
+	}
+	docB, ok := b.(*Document)
+	if !ok {
+		return nil, fmt.Errorf("unable to compare documents of type %T", b)
+	}
+
+	cmp := &Comparison{
+		Shared: make([]*Alignment, 0),
+		OnlyA:  make([]*Alignment, 0),
+		OnlyB:  make([]*Alignment, 0),
+	}
+	for _, al := range docA.align(ordered(docA.Sentences), docB.Sentences) {
+		if al.Score >= threshold {
+			cmp.Shared = append(cmp.Shared, al)
+		} else {
+			cmp.OnlyA = append(cmp.OnlyA, al)
+		}
+	}
+	for _, al := range docA.align(ordered(docB.Sentences), docA.Sentences) {
+		if al.Score < threshold {
+			cmp.OnlyB = append(cmp.OnlyB, al)
+		}
+	}
+
+	return cmp, nil
+}
+
+// align aligns each of the sentences with its most similar sentence among the others.
+func (doc *Document) align(sents, others []*basically.Sentence) []*Alignment {
+	alignments := make([]*Alignment, 0, len(sents))
+	for _, sent := range sents {
+		al := &Alignment{Sentence: sent}
+		for _, other := range others {
+			if score := doc.overlap(sent, other); al.Match == nil || score > al.Score {
+				al.Match, al.Score = other, score
+			}
+		}
+		alignments = append(alignments, al)
+	}
+	return alignments
+}
+
+// ordered returns a copy of the sentences, sorted by their order in the text.
+func ordered(sents []*basically.Sentence) []*basically.Sentence {
+	ret := make([]*basically.Sentence, len(sents))
+	copy(ret, sents)
+	sort.SliceStable(ret, func(i, j int) bool { return ret[i].Order < ret[j].Order })
+	return ret
+}
@@ -1,185 +1,683 @@
-package document
-
-import (
-	"fmt"
-	"sort"
-	"unicode/utf8"
-
-	"github.com/algao1/basically"
-	"github.com/algao1/basically/document/sentence"
-)
-
-// A Config represents a setting that changes the summarization process.
-// For example, it may configure a custom token filter:
-// 		doc := document.Create(..., document.WithCustomFilter(filter))
-type Config func(cfgs *Configs)
-
-// Configs control the summarization process.
-type Configs struct {
-	sfilter      basically.TokenFilter // Default uses matcher.NVFilter.
-	kwfilter     basically.TokenFilter // Default uses matcher.NVNSFilter.
-	similarity   basically.Similarity  // Default uses sentence.NVFilter.
-	quotations   bool                  // Default disables merging of sentences within quotations.
-	conjunctions bool                  // Default removes conjunctions from the beginning of sentences.
-	focus        bool                  // Default uses the first sentence as focus if a focus sentence is not provided.
-	threshold    float64               // Default sets the similarity threshold to 0.65 as recommended in Biased TextRank.
-}
-
-// WithCustomSFilter allows for a custom (black/white) token filter to be set
-// for sentence extraction.
-func WithCustomSFilter(filter basically.TokenFilter) Config {
-	return func(cfgs *Configs) { cfgs.sfilter = filter }
-}
-
-// WithCustomKWFilter allows for a custom (black/white) token filter to be set
-// for keyword extraction.
-func WithCustomKWFilter(filter basically.TokenFilter) Config {
-	return func(cfgs *Configs) { cfgs.kwfilter = filter }
-}
-
-// WithCustomSimilarity allows for a custom similarity function to be set.
-func WithCustomSimilarity(similarity basically.Similarity) Config {
-	return func(cfgs *Configs) { cfgs.similarity = similarity }
-}
-
-// WithConjunctions disables removing conjunctions from the start of sentences.
-func WithConjunctions() Config {
-	return func(cfgs *Configs) { cfgs.conjunctions = true }
-}
-
-// WithCustomThreshold sets the similarity threshold as per the specification.
-// Lower threshold values correspond with sparser graphs, and higher threshold values
-// correspond with denser graphs.
-func WithCustomThreshold(threshold float64) Config {
-	return func(cfgs *Configs) { cfgs.threshold = threshold }
-}
-
-// WithoutMergeQuotations disables merging sentences within quotations.
-func WithoutMergeQuotations() Config {
-	return func(cfgs *Configs) { cfgs.quotations = false }
-}
-
-// WithoutFocus disables the use of a focus for ranking sentence scores.
-func WithoutFocus() Config {
-	return func(cfgs *Configs) { cfgs.focus = false }
-}
-
-// Document is an implementation of basically.Document.
-type Document struct {
-	// Configurations and dependency injection.
-	Configs     *Configs
-	Summarizer  basically.Summarizer
-	Highlighter basically.Highlighter
-	Parser      basically.Parser
-	// Document related information.
-	Sentences []*basically.Sentence
-	Words     []*basically.Token
-	CharCount int
-	SummCount int
-}
-
-func Create(text string, s basically.Summarizer, h basically.Highlighter,
-	p basically.Parser, cfgs ...Config) (basically.Document, error) {
-	// Initializes and applies the configurations.
-	// The threshold is set based on the results from https://www.aclweb.org/anthology/P04-3020.pdf.
-	m, err := sentence.CreateMatcher()
-	if err != nil {
-		return nil, fmt.Errorf("%q: %w", "unable to create matcher", err)
-	}
-
-	configs := Configs{
-		sfilter:      m.NVFilter,
-		kwfilter:     m.NVNSFilter,
-		similarity:   sentence.DefaultSimilarity,
-		conjunctions: false,
-		focus:        true,
-		threshold:    0.65,
-	}
-	for _, applyConfig := range cfgs {
-		applyConfig(&configs)
-	}
-
-	// Parses the document into sentences and words.
-	sents, words, err := p.ParseDocument(text, configs.quotations)
-	if err != nil {
-		return nil, fmt.Errorf("%q: %w", "unable to parse document", err)
-	}
-
-	// Create and return the document.
-	doc := &Document{
-		Configs:     &configs,
-		Summarizer:  s,
-		Highlighter: h,
-		Parser:      p,
-		Sentences:   sents,
-		Words:       words,
-		CharCount:   utf8.RuneCountInString(text),
-		SummCount:   0,
-	}
-
-	return doc, nil
-}
-
-// Summarize returns a summary of given length corresponding to the top relevant phrases.
-// A focus string may be provided to adjust the summary contents.
-func (doc *Document) Summarize(length int, threshold float64, raw string) ([]*basically.Sentence, error) {
-	// Sanity check to ensure that the given text is sufficiently large.
-	if length > len(doc.Sentences) {
-		return nil, fmt.Errorf("text is too short")
-	}
-
-	var focus *basically.Sentence
-	if len(raw) > 0 {
-		sents, _, err := doc.Parser.ParseDocument(raw, doc.Configs.quotations)
-		if err != nil {
-			return nil, fmt.Errorf("%q: %w", "unable to parse focus sentence", err)
-		}
-
-		if len(sents) > 0 {
-			focus = sents[0]
-		}
-	} else if doc.Configs.focus {
-		focus = doc.Sentences[0]
-	}
-
-	// Initializes and ranks the sentences.
-	doc.Summarizer.Initialize(doc.Sentences, doc.Configs.similarity, doc.Configs.sfilter, focus, doc.Configs.threshold)
-	doc.Summarizer.Rank(5)
-
-	// Sorts the ranked sentences by score.
-	sort.SliceStable(doc.Sentences, func(i, j int) bool { return doc.Sentences[i].Score > doc.Sentences[j].Score })
-
-	// Calculates the summary word count, and limits the summary based on threshold.
-	for idx, sent := range doc.Sentences[:length] {
-		doc.SummCount += utf8.RuneCountInString(sent.Raw)
-		if threshold > 0 && float64(doc.SummCount)/float64(doc.CharCount) > threshold {
-			length = idx + 1
-			break
-		}
-	}
-
-	// Sorts the ranked sentences by sentence order in the original text.
-	sort.SliceStable(doc.Sentences[:length], func(i, j int) bool { return doc.Sentences[i].Order < doc.Sentences[j].Order })
-
-	// Handle conjunctions at the beginning of sentences.
-	if !doc.Configs.conjunctions {
-		for _, sent := range doc.Sentences[:length] {
-			sentence.RemoveConj(sent)
-		}
-	}
-
-	return doc.Sentences[:length], nil
-}
-
-// Highlight returns a list of the keywords in the document.
-func (doc *Document) Highlight(length int, merge bool) ([]*basically.Keyword, error) {
-	// Initialize the highlighter, and apply the ranking algorithm.
-	doc.Highlighter.Initialize(doc.Words, doc.Configs.kwfilter, 2)
-	doc.Highlighter.Rank(25)
-	return doc.Highlighter.Highlight(length, merge)
-}
-
-// Characters returns the character count of the original text, and the summarized text (if any).
-func (doc *Document) Characters() (int, int) {
-	return doc.CharCount, doc.SummCount
-}
+package document
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/algao1/basically"
+	"github.com/algao1/basically/document/sentence"
+)
+
+// A Config represents a setting that changes the summarization process.
+// For example, it may configure a custom token filter:
+// 		doc := document.Create(..., document.WithCustomFilter(filter))
+type Config func(cfgs *Configs)
+
+// Configs control the summarization process.
+type Configs struct {
+	sfilter      basically.TokenFilter       // Default uses matcher.NVFilter.
+	kwfilter     basically.TokenFilter       // Default uses matcher.NVNSFilter.
+	similarity   basically.Similarity        // Default uses sentence.NVFilter.
+	quotations   bool                        // Default disables merging of sentences within quotations.
+	conjunctions bool                        // Default removes conjunctions from the beginning of sentences.
+	focus        bool                        // Default uses the first sentence as focus if a focus sentence is not provided.
+	threshold    float64                     // Default sets the similarity threshold to 0.65 as recommended in Biased TextRank.
+	entity       bool                        // Default treats the focus string as a sentence, rather than a named-entity.
+	aliases      []string                    // Default uses no additional aliases for the focus entity.
+	window       int                         // Default sets the co-occurrence window for keywords to 2.
+	speaker      bool                        // Default treats the focus string as a sentence, rather than a speaker.
+	balance      bool                        // Default ranks sentences regardless of their speaker.
+	redundancy   float64                     // Default keeps redundant sentences (except in multi-document summaries).
+	chunk        int                         // Default ranks all sentences together, rather than hierarchically.
+	summarizers  func() basically.Summarizer // Default ranks the chunks of hierarchical summaries one at a time.
+	previous     []string                    // Default treats every sentence as novel, as no documents were previously seen.
+	topics       int                         // Default splits sections at headings only.
+	compressor   *sentence.Compressor        // Default leaves summary sentences uncompressed.
+}
+
+// WithCustomSFilter allows for a custom (black/white) token filter to be set
+// for sentence extraction.
+func WithCustomSFilter(filter basically.TokenFilter) Config {
+	return func(cfgs *Configs) { cfgs.sfilter = filter }
+}
+
+// WithCustomKWFilter allows for a custom (black/white) token filter to be set
+// for keyword extraction.
+func WithCustomKWFilter(filter basically.TokenFilter) Config {
+	return func(cfgs *Configs) { cfgs.kwfilter = filter }
+}
+
+// WithCustomSimilarity allows for a custom similarity function to be set.
+func WithCustomSimilarity(similarity basically.Similarity) Config {
+	return func(cfgs *Configs) { cfgs.similarity = similarity }
+}
+
+// WithConjunctions disables removing conjunctions from the start of sentences.
+func WithConjunctions() Config {
+	return func(cfgs *Configs) { cfgs.conjunctions = true }
+}
+
+// WithCustomThreshold sets the similarity threshold as per the specification.
+// Lower threshold values correspond with sparser graphs, and higher threshold values
+// correspond with denser graphs.
+func WithCustomThreshold(threshold float64) Config {
+	return func(cfgs *Configs) { cfgs.threshold = threshold }
+}
+
+// WithKeywordWindow sets the size of the co-occurrence window used for keyword extraction.
+// Larger windows connect words that are further apart.
+func WithKeywordWindow(window int) Config {
+	return func(cfgs *Configs) { cfgs.window = window }
+}
+
+// WithoutMergeQuotations disables merging sentences within quotations.
+func WithoutMergeQuotations() Config {
+	return func(cfgs *Configs) { cfgs.quotations = false }
+}
+
+// WithoutFocus disables the use of a focus for ranking sentence scores.
+func WithoutFocus() Config {
+	return func(cfgs *Configs) { cfgs.focus = false }
+}
+
+// WithEntityFocus treats the focus string given to Summarize as a named-entity, such as a
+// person or organization. Sentences are then biased towards those mentioning the entity,
+// or any of its aliases. Aliases are discovered from the document's entities, and may
+// also be given explicitly.
+func WithEntityFocus(aliases ...string) Config {
+	return func(cfgs *Configs) {
+		cfgs.entity = true
+		cfgs.aliases = aliases
+	}
+}
+
+// WithSpeakerFocus treats the focus string given to Summarize as the name of a speaker
+// (in transcripts), so that the summary favours what they said.
+func WithSpeakerFocus() Config {
+	return func(cfgs *Configs) { cfgs.speaker = true }
+}
+
+// WithSpeakerBalance balances the summary across speakers (in transcripts), by weighting the
+// bias of each sentence inversely to the number of sentences of its speaker. Otherwise, the
+// most talkative speakers tend to dominate the summary.
+func WithSpeakerBalance() Config {
+	return func(cfgs *Configs) { cfgs.balance = true }
+}
+
+// WithRedundancy removes sentences from the summary which are redundant with a higher-ranked
+// sentence. Two sentences are redundant if their similarity, relative to the similarity of the
+// shorter sentence with itself, exceeds the threshold (between 0 and 1). Lower thresholds remove
+// more sentences, and a threshold of 0 disables redundancy removal.
+func WithRedundancy(threshold float64) Config {
+	return func(cfgs *Configs) { cfgs.redundancy = threshold }
+}
+
+// WithPrevious sets the texts of previously seen documents (such as yesterday's articles), so that
+// the summary covers what is new. The bias and score of each sentence are lowered by its similarity
+// to the most similar previously seen sentence (relative to the shorter sentence's similarity with
+// itself), favouring novel content.
+func WithPrevious(texts ...string) Config {
+	return func(cfgs *Configs) { cfgs.previous = texts }
+}
+
+// WithHierarchy summarizes documents longer than size sentences hierarchically, which is faster for
+// very long documents (such as books). The sentences are split into chunks of at most size sentences,
+// following sections where possible, and the top sentences of each chunk are combined and summarized
+// again, until they fit in a single chunk. If summarizers is given, chunks are ranked in parallel with
+// a new Summarizer for each chunk. Summarized sentences keep their provenance (order, section and offsets).
+// Chunks must have at least 3 sentences, so that each pass removes sentences.
+func WithHierarchy(size int, summarizers func() basically.Summarizer) Config {
+	return func(cfgs *Configs) {
+		cfgs.chunk = size
+		cfgs.summarizers = summarizers
+	}
+}
+
+// WithTopics also starts a new section at every topic boundary (see sentence.TextTiling), which
+// is useful for long texts without headings, such as transcripts and reports. Block is the number
+// of sentences compared on either side of each boundary (e.g. 3).
+func WithTopics(block int) Config {
+	return func(cfgs *Configs) { cfgs.topics = block }
+}
+
+// WithCompression compresses the sentences of summaries with the given compressor, removing
+// parentheticals, appositives and other parts which rarely carry their main point.
+func WithCompression(c *sentence.Compressor) Config {
+	return func(cfgs *Configs) { cfgs.compressor = c }
+}
+
+// Document is an implementation of basically.Document. Its methods beyond the interface (such as
+// SummarizeSections) are available by asserting the result of Create to *Document.
+type Document struct {
+	// Configurations and dependency injection.
+	Configs     *Configs
+	Summarizer  basically.Summarizer
+	Highlighter basically.Highlighter
+	Parser      basically.Parser
+	// Document related information.
+	Sentences []*basically.Sentence
+	Words     []*basically.Token
+	Previous  []*basically.Sentence // Sentences of previously seen documents.
+	CharCount int
+	SummCount int
+}
+
+func Create(text string, s basically.Summarizer, h basically.Highlighter,
+	p basically.Parser, cfgs ...Config) (basically.Document, error) {
+	configs, err := configure(cfgs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parses the document into sentences and words.
+	sents, words, err := p.ParseDocument(text, configs.quotations)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to parse document", err)
+	}
+
+	// Create and return the document.
+	doc := &Document{
+		Configs:     configs,
+		Summarizer:  s,
+		Highlighter: h,
+		Parser:      p,
+		Sentences:   sents,
+		Words:       words,
+		CharCount:   utf8.RuneCountInString(text),
+		SummCount:   0,
+	}
+	if err := doc.parsePrevious(); err != nil {
+		return nil, err
+	}
+	doc.segment()
+
+	return doc, nil
+}
+
+// configure initializes and applies the configurations.
+func configure(cfgs []Config) (*Configs, error) {
+	// The threshold is set based on the results from https://www.aclweb.org/anthology/P04-3020.pdf.
+	m, err := sentence.CreateMatcher()
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to create matcher", err)
+	}
+
+	configs := Configs{
+		sfilter:      m.NVFilter,
+		kwfilter:     m.NVNSFilter,
+		similarity:   sentence.DefaultSimilarity,
+		conjunctions: false,
+		focus:        true,
+		threshold:    0.65,
+		window:       2,
+	}
+	for _, applyConfig := range cfgs {
+		applyConfig(&configs)
+	}
+
+	if configs.chunk > 0 && configs.chunk < minChunk {
+		return nil, fmt.Errorf("chunks of %d sentences are too small, need at least %d", configs.chunk, minChunk)
+	}
+	return &configs, nil
+}
+
+// Summarize returns a summary of given length corresponding to the top relevant phrases.
+// A focus string may be provided to adjust the summary contents. If WithEntityFocus is set,
+// the focus string is treated as a named-entity instead, and if WithSpeakerFocus is set,
+// as the name of a speaker. If the threshold is positive, the summary ends with the first
+// sentence whose characters, together with those before it, exceed that fraction of the text's.
+func (doc *Document) Summarize(length int, threshold float64, raw string) ([]*basically.Sentence, error) {
+	// Sanity check to ensure that the given text is sufficiently large.
+	if length > len(doc.Sentences) {
+		return nil, fmt.Errorf("text is too short")
+	}
+
+	sents, err := doc.rank(length, raw)
+	if err != nil {
+		return nil, err
+	}
+	if length > len(sents) {
+		length = len(sents)
+	}
+
+	// Removes sentences redundant with higher-ranked ones.
+	if doc.Configs.redundancy > 0 {
+		length = doc.removeRedundant(sents, length)
+	}
+
+	// Limits the summary based on threshold.
+	var count int
+	for idx, sent := range sents[:length] {
+		count += utf8.RuneCountInString(sent.Raw)
+		if threshold > 0 && float64(count)/float64(doc.CharCount) > threshold {
+			length = idx + 1
+			break
+		}
+	}
+
+	return doc.finish(sents[:length]), nil
+}
+
+// rank ranks the sentences for a summary of the given length, focusing on the focus string
+// (see Summarize), and returns them sorted by score.
+func (doc *Document) rank(length int, raw string) ([]*basically.Sentence, error) {
+	var focus *basically.Sentence
+	biased := false
+	if len(raw) > 0 && doc.Configs.entity {
+		if err := doc.biasEntity(raw); err != nil {
+			return nil, err
+		}
+		biased = true
+	} else if len(raw) > 0 && doc.Configs.speaker {
+		if err := doc.biasSpeaker(raw); err != nil {
+			return nil, err
+		}
+		biased = true
+	} else if len(raw) > 0 {
+		sents, _, err := doc.Parser.ParseDocument(raw, doc.Configs.quotations)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", "unable to parse focus sentence", err)
+		}
+
+		if len(sents) > 0 {
+			focus = sents[0]
+		}
+	} else if doc.Configs.focus {
+		focus = doc.lead()
+	}
+
+	// Initializes and ranks the sentences, hierarchically for long documents if enabled.
+	r := doc.ranking(focus, biased)
+	var sents []*basically.Sentence
+	if doc.Configs.chunk > 0 && len(doc.Sentences) > doc.Configs.chunk {
+		sents = doc.reduce(length, r)
+	} else {
+		sents = make([]*basically.Sentence, len(doc.Sentences))
+		copy(sents, doc.Sentences)
+		r.rank(doc.Summarizer, sents)
+	}
+
+	// Sorts the ranked sentences by score.
+	sort.SliceStable(sents, func(i, j int) bool { return sents[i].Score > sents[j].Score })
+	return sents, nil
+}
+
+// finish sorts the summary by sentence order in the original text, compresses its sentences
+// (if enabled), handles conjunctions at the beginning of sentences, and counts the characters
+// of the summary.
+func (doc *Document) finish(sents []*basically.Sentence) []*basically.Sentence {
+	sort.SliceStable(sents, func(i, j int) bool { return sents[i].Order < sents[j].Order })
+
+	doc.SummCount = 0
+	for idx := range sents {
+		sents[idx] = compress(doc.Configs, sents[idx])
+		if !doc.Configs.conjunctions {
+			sentence.RemoveConj(sents[idx])
+		}
+		doc.SummCount += utf8.RuneCountInString(sents[idx].Raw)
+	}
+	return sents
+}
+
+// compress returns a copy of the sentence, compressed if enabled, so that the sentences of the
+// document are left whole for later summaries (which also remove conjunctions from the copy).
+func compress(cfgs *Configs, sent *basically.Sentence) *basically.Sentence {
+	cp := *sent
+	if cfgs.compressor != nil {
+		cfgs.compressor.Compress(&cp)
+	}
+	return &cp
+}
+
+// A ranking ranks sentences for a summary, applying the biases shared by every ranking of the
+// summary (for hierarchical summaries).
+type ranking struct {
+	doc     *Document
+	focus   *basically.Sentence
+	bias    map[*basically.Sentence]float64 // The biases assigned before ranking, used without a focus.
+	balance map[*basically.Sentence]float64 // The speaker weights of the biases (if balanced).
+	novelty map[*basically.Sentence]float64 // The novelty weights of the biases and scores.
+}
+
+// ranking returns the ranking of the document's sentences. If biased is not set, the biases
+// assigned before ranking are reset, as they are left over from previous summaries.
+func (doc *Document) ranking(focus *basically.Sentence, biased bool) *ranking {
+	r := &ranking{
+		doc:     doc,
+		focus:   focus,
+		bias:    make(map[*basically.Sentence]float64, len(doc.Sentences)),
+		balance: doc.balanceSpeakers(),
+		novelty: doc.novelty(),
+	}
+	for _, sent := range doc.Sentences {
+		r.bias[sent] = 1
+		if biased {
+			r.bias[sent] = sent.Bias
+		}
+	}
+	return r
+}
+
+// rank initializes the summarizer with the sentences, and ranks them. Scores and biases left over
+// from previous rankings are reset, so that every ranking of the sentences is the same.
+func (r *ranking) rank(s basically.Summarizer, sents []*basically.Sentence) {
+	cfgs := r.doc.Configs
+	for _, sent := range sents {
+		sent.Score, sent.Bias = 0, 0
+	}
+	s.Initialize(sents, cfgs.similarity, cfgs.sfilter, r.focus, cfgs.threshold)
+	for _, sent := range sents {
+		if r.focus == nil {
+			sent.Bias = r.bias[sent]
+		}
+		sent.Bias *= r.balance[sent] * r.novelty[sent]
+	}
+
+	s.Rank(5)
+	for _, sent := range sents {
+		sent.Score *= r.novelty[sent]
+	}
+}
+
+// lead returns the default focus of the summary: the first sentence of the document, or the
+// first sentences of every source combined (in multi-document summaries).
+func (doc *Document) lead() *basically.Sentence {
+	leads := make(map[string]*basically.Sentence)
+	sources := make([]string, 0)
+	for _, sent := range doc.Sentences {
+		lead, ok := leads[sent.Source]
+		if !ok {
+			sources = append(sources, sent.Source)
+		}
+		if !ok || sent.Order < lead.Order {
+			leads[sent.Source] = sent
+		}
+	}
+
+	if len(sources) == 1 {
+		return leads[sources[0]]
+	}
+
+	sort.Strings(sources)
+	focus := &basically.Sentence{}
+	for _, source := range sources {
+		focus.Tokens = append(focus.Tokens, leads[source].Tokens...)
+	}
+	return focus
+}
+
+// removeRedundant moves the top (at most length) sentences which are not redundant with any
+// higher-ranked sentence to the front, keeping their order, and returns their number.
+// The sentences must be sorted by score.
+func (doc *Document) removeRedundant(sents []*basically.Sentence, length int) int {
+	kept := make([]*basically.Sentence, 0, length)
+	rest := make([]*basically.Sentence, 0, len(sents))
+	for _, sent := range sents {
+		if len(kept) < length && !doc.redundant(sent, kept) {
+			kept = append(kept, sent)
+		} else {
+			rest = append(rest, sent)
+		}
+	}
+
+	copy(sents, append(kept, rest...))
+	return len(kept)
+}
+
+// redundant determines if the sentence is redundant with any of the other sentences.
+func (doc *Document) redundant(sent *basically.Sentence, others []*basically.Sentence) bool {
+	for _, other := range others {
+		if doc.overlap(sent, other) > doc.Configs.redundancy {
+			return true
+		}
+	}
+	return false
+}
+
+// overlap returns the similarity of the two sentences, relative to the similarity of the shorter
+// sentence with itself. The overlap is between 0 (unrelated) and 1 (duplicates) for the default
+// similarity.
+func (doc *Document) overlap(a, b *basically.Sentence) float64 {
+	similar := func(a, b *basically.Sentence) float64 {
+		return doc.Configs.similarity(a.Tokens, b.Tokens, doc.Configs.sfilter)
+	}
+
+	self := math.Min(similar(a, a), similar(b, b))
+	if self <= 0 {
+		return 0
+	}
+	return math.Min(similar(a, b)/self, 1)
+}
+
+// segment starts a new section at every topic boundary, if enabled.
+func (doc *Document) segment() {
+	if doc.Configs.topics <= 0 {
+		return
+	}
+
+	sents := ordered(doc.Sentences)
+	bounds := sentence.TextTiling(sents, doc.Configs.kwfilter, doc.Configs.topics)
+	prev, section := 0, 0
+	for idx, sent := range sents {
+		topic := len(bounds) > 0 && bounds[0].Sentence == idx
+		if topic {
+			bounds = bounds[1:]
+		}
+		if idx > 0 && (sent.Section != prev || topic) {
+			section++
+		}
+		prev = sent.Section
+		sent.Section = section
+	}
+}
+
+// parsePrevious parses the previously seen documents (if any).
+func (doc *Document) parsePrevious() error {
+	for _, text := range doc.Configs.previous {
+		sents, _, err := doc.Parser.ParseDocument(text, doc.Configs.quotations)
+		if err != nil {
+			return fmt.Errorf("%q: %w", "unable to parse previous document", err)
+		}
+		doc.Previous = append(doc.Previous, sents...)
+	}
+	return nil
+}
+
+// novelty returns the novelty of every sentence: 1 minus its overlap with the most similar
+// previously seen sentence.
+func (doc *Document) novelty() map[*basically.Sentence]float64 {
+	novelty := make(map[*basically.Sentence]float64, len(doc.Sentences))
+	for _, sent := range doc.Sentences {
+		novelty[sent] = 1
+		for _, prev := range doc.Previous {
+			novelty[sent] = math.Min(novelty[sent], 1-doc.overlap(sent, prev))
+		}
+	}
+	return novelty
+}
+
+// SummarizeMessages returns a summary of each message (in email threads), in order, of at most
+// the given length. Each message is ranked on its own, so that long messages do not crowd out
+// the others.
+func (doc *Document) SummarizeMessages(length int, threshold float64) ([][]*basically.Sentence, error) {
+	msgs := doc.group(func(sent *basically.Sentence) int { return sent.Message })
+	doc.SummCount = 0
+
+	sums := make([][]*basically.Sentence, 0, len(msgs))
+	for _, msg := range msgs {
+		n := length
+		if n > len(msg.Sentences) {
+			n = len(msg.Sentences)
+		}
+
+		sum, err := msg.Summarize(n, threshold, "")
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", "unable to summarize message", err)
+		}
+		doc.SummCount += msg.SummCount
+		sums = append(sums, sum)
+	}
+
+	return sums, nil
+}
+
+// group splits the document into smaller documents (sharing its configuration and dependencies),
+// grouping the sentences and words by the given key, in order of appearance.
+func (doc *Document) group(key func(sent *basically.Sentence) int) []*Document {
+	groups := make(map[int]*Document)
+	order := make([]int, 0)
+	bySentence := make(map[int]*Document)
+	for _, sent := range ordered(doc.Sentences) {
+		group, ok := groups[key(sent)]
+		if !ok {
+			group = &Document{
+				Configs:     doc.Configs,
+				Summarizer:  doc.Summarizer,
+				Highlighter: doc.Highlighter,
+				Parser:      doc.Parser,
+				Words:       make([]*basically.Token, 0),
+				Previous:    doc.Previous,
+			}
+			groups[key(sent)] = group
+			order = append(order, key(sent))
+		}
+		group.Sentences = append(group.Sentences, sent)
+		group.CharCount += utf8.RuneCountInString(sent.Raw)
+		bySentence[sent.Order] = group
+	}
+	for _, word := range doc.Words {
+		if group, ok := bySentence[word.Sentence]; ok {
+			group.Words = append(group.Words, word)
+		}
+	}
+
+	docs := make([]*Document, 0, len(order))
+	for _, k := range order {
+		docs = append(docs, groups[k])
+	}
+	return docs
+}
+
+// biasEntity sets the bias of every sentence to the number of times it mentions
+// the named-entity (or any of its aliases).
+func (doc *Document) biasEntity(name string) error {
+	aliases := sentence.EntityAliases(name, doc.Configs.aliases, doc.Entities())
+
+	var total int
+	for _, sent := range doc.Sentences {
+		mentions := sentence.Mentions(sent, aliases)
+		sent.Bias = float64(mentions)
+		total += mentions
+	}
+
+	if total == 0 {
+		return fmt.Errorf("entity %q not found in text", name)
+	}
+	return nil
+}
+
+// biasSpeaker sets the bias of every sentence to 1 if it was said by the speaker, and 0 otherwise.
+// Speaker names are matched case-insensitively.
+func (doc *Document) biasSpeaker(name string) error {
+	found := false
+	for _, sent := range doc.Sentences {
+		sent.Bias = 0
+		if strings.EqualFold(sent.Speaker, name) {
+			sent.Bias = 1
+			found = true
+		}
+	}
+
+	if !found {
+		return fmt.Errorf("speaker %q not found in text", name)
+	}
+	return nil
+}
+
+// balanceSpeakers returns the weight of every sentence's bias: 1 if WithSpeakerBalance is not set,
+// and otherwise inversely proportional to the number of sentences of its speaker, so that each
+// speaker carries the same total weight.
+func (doc *Document) balanceSpeakers() map[*basically.Sentence]float64 {
+	counts := make(map[string]int)
+	for _, sent := range doc.Sentences {
+		counts[sent.Speaker]++
+	}
+
+	weights := make(map[*basically.Sentence]float64, len(doc.Sentences))
+	for _, sent := range doc.Sentences {
+		weights[sent] = 1
+		if doc.Configs.balance {
+			weights[sent] = float64(len(doc.Sentences)) / float64(len(counts)*counts[sent.Speaker])
+		}
+	}
+	return weights
+}
+
+// Highlight returns a list of the keywords in the document.
+func (doc *Document) Highlight(length int, merge bool) ([]*basically.Keyword, error) {
+	// Initialize the highlighter, and apply the ranking algorithm.
+	doc.Highlighter.Initialize(doc.Words, doc.Configs.kwfilter, doc.Configs.window)
+	doc.Highlighter.Rank(25)
+	return doc.Highlighter.Highlight(length, merge)
+}
+
+// HighlightSpeaker returns a list of the keywords used by the speaker (in transcripts).
+// Speaker names are matched case-insensitively.
+func (doc *Document) HighlightSpeaker(speaker string, length int, merge bool) ([]*basically.Keyword, error) {
+	said := make(map[int]bool)
+	for _, sent := range doc.Sentences {
+		if strings.EqualFold(sent.Speaker, speaker) {
+			said[sent.Order] = true
+		}
+	}
+	if len(said) == 0 {
+		return nil, fmt.Errorf("speaker %q not found in text", speaker)
+	}
+
+	words := make([]*basically.Token, 0)
+	for _, word := range doc.Words {
+		if said[word.Sentence] {
+			words = append(words, word)
+		}
+	}
+
+	doc.Highlighter.Initialize(words, doc.Configs.kwfilter, doc.Configs.window)
+	doc.Highlighter.Rank(25)
+	return doc.Highlighter.Highlight(length, merge)
+}
+
+// Speakers returns the speakers in the document (in transcripts), in order of appearance.
+func (doc *Document) Speakers() []string {
+	sents := ordered(doc.Sentences)
+
+	seen := make(map[string]bool)
+	speakers := make([]string, 0)
+	for _, sent := range sents {
+		if sent.Speaker != "" && !seen[sent.Speaker] {
+			seen[sent.Speaker] = true
+			speakers = append(speakers, sent.Speaker)
+		}
+	}
+	return speakers
+}
+
+// Entities returns the named-entities in the document, in order of appearance.
+// Entities are only available if extracted by the parser.
+func (doc *Document) Entities() []*basically.Entity {
+	// Sentences may have been reordered during summarization.
+	sents := ordered(doc.Sentences)
+
+	ents := make([]*basically.Entity, 0)
+	for _, sent := range sents {
+		ents = append(ents, sent.Entities...)
+	}
+	return ents
+}
+
+// Characters returns the character count of the original text, and the summarized text (if any).
+func (doc *Document) Characters() (int, int) {
+	return doc.CharCount, doc.SummCount
+}
@@ -3,14 +3,526 @@ package document
 import (
 	"log"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"unicode/utf8"
 
+	"github.com/algao1/basically"
 	"github.com/algao1/basically/btrank"
+	"github.com/algao1/basically/document/sentence"
 	"github.com/algao1/basically/parser"
 	"github.com/algao1/basically/trank"
+	"github.com/stretchr/testify/assert"
 )
 
+func readTestdata(t *testing.T) []string {
+	files, err := filepath.Glob("../testdata/*.txt")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("failed reading testdata: %v", err)
+	}
+
+	texts := make([]string, 0, len(files))
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("failed reading %s: %s", file, err)
+		}
+		texts = append(texts, string(data))
+	}
+	return texts
+}
+
+func TestHighlightDeterministic(t *testing.T) {
+	p := parser.Create()
+	for _, text := range readTestdata(t) {
+		var expected []*basically.Keyword
+		for run := 0; run < 5; run++ {
+			doc, err := Create(text, &btrank.BiasedTextRank{}, &trank.KWTextRank{}, p)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			kws, err := doc.Highlight(10, true)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if run == 0 {
+				expected = kws
+				continue
+			}
+			assert.Equal(t, expected, kws)
+		}
+	}
+}
+
+func TestSpeakers(t *testing.T) {
+	text := "Alice: We shipped the new billing service yesterday.\n" +
+		"Bob: The deployment pipeline is still failing on staging.\n" +
+		"The build agents run out of memory, and the pipeline times out after an hour.\n" +
+		"Alice: I can look at the staging cluster after lunch.\n" +
+		"Carol (PM): Let's make sure billing ships before the end of the sprint.\n" +
+		"Bob: I will ask the infrastructure team for larger agents.\n" +
+		"Carol (PM): Great, we can review the release plan on Friday.\n"
+
+	p := parser.Create(parser.WithTranscript())
+	doc, err := Create(text, &btrank.BiasedTextRank{}, &trank.KWTextRank{}, p, WithSpeakerFocus())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []string{"Alice", "Bob", "Carol"}, doc.(*Document).Speakers())
+
+	sums, err := doc.Summarize(1, 0, "bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "Bob", sums[0].Speaker)
+
+	_, err = doc.Summarize(1, 0, "Dave")
+	assert.Error(t, err)
+
+	kws, err := doc.(*Document).HighlightSpeaker("Bob", 3, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NotEmpty(t, kws)
+	for _, kw := range kws {
+		assert.NotContains(t, []string{"billing", "lunch", "sprint"}, kw.Word)
+	}
+}
+
+func TestBiasEntity(t *testing.T) {
+	// sent creates a sentence from space-separated word/TAG pairs, with the given entities
+	// (as spans of tokens).
+	sent := func(order int, pairs string, spans ...[2]int) *basically.Sentence {
+		s := &basically.Sentence{Order: order}
+		for _, pair := range strings.Fields(pairs) {
+			idx := strings.LastIndex(pair, "/")
+			s.Tokens = append(s.Tokens, &basically.Token{Text: pair[:idx], Tag: pair[idx+1:]})
+		}
+		for _, span := range spans {
+			s.Entities = append(s.Entities, &basically.Entity{Label: "PERSON", Tokens: s.Tokens[span[0]:span[1]]})
+		}
+		return s
+	}
+
+	doc := &Document{
+		Configs: &Configs{aliases: []string{"the chancellor"}},
+		Sentences: []*basically.Sentence{
+			sent(0, "angela/NNP merkel/NNP met/VBD the/DT press/NN ./.", [2]int{0, 2}),
+			sent(1, "merkel/NNP spoke/VBD ,/, and/CC merkel/NNP left/VBD ./.", [2]int{0, 1}, [2]int{4, 5}),
+			sent(2, "the/DT chancellor/NN agreed/VBD ./."),
+			sent(3, "it/PRP rained/VBD ./."),
+			// Aliases found from entities only match proper nouns.
+			sent(4, "a/DT merkel/NN is/VBZ rare/JJ ./."),
+		},
+	}
+
+	if err := doc.biasEntity("Angela Merkel"); err != nil {
+		t.Fatal(err)
+	}
+	bias := make([]float64, 0, len(doc.Sentences))
+	for _, s := range doc.Sentences {
+		bias = append(bias, s.Bias)
+	}
+	assert.Equal(t, []float64{1, 2, 1, 0, 0}, bias)
+
+	doc.Configs.aliases = nil
+	assert.Error(t, doc.biasEntity("Olaf Scholz"))
+}
+
+func TestCreateMulti(t *testing.T) {
+	texts := []string{
+		"The city council approved a new budget on Tuesday. The budget increases funding for public " +
+			"transit by 20 percent. Critics argued the budget raises property taxes for homeowners.",
+		"On Tuesday the city council approved the new budget. Funding for public transit increases by " +
+			"20 percent under the budget. The council also approved a new bike lane program.",
+	}
+
+	doc, err := CreateMulti(texts, []string{"a", "b"}, &btrank.BiasedTextRank{}, &trank.KWTextRank{}, parser.Create())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sums, err := doc.Summarize(4, 0, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	budget := 0
+	for idx, sum := range sums {
+		assert.Contains(t, []string{"a", "b"}, sum.Source)
+		if idx > 0 {
+			assert.Less(t, sums[idx-1].Order, sum.Order)
+		}
+		if strings.Contains(sum.Raw, "approved") && strings.Contains(sum.Raw, "budget") {
+			budget++
+		}
+	}
+	assert.LessOrEqual(t, budget, 1, "redundant sentences should be removed")
+}
+
+func TestPrevious(t *testing.T) {
+	previous := "The city council approved a new budget on Tuesday. The budget increases funding for " +
+		"public transit by 20 percent."
+	text := "On Tuesday the city council approved the new budget. Funding for public transit increases " +
+		"by 20 percent under the budget. The council also approved a new bike lane program along Main " +
+		"Street. Construction of the bike lanes is expected to begin in the spring."
+
+	doc, err := Create(text, &btrank.BiasedTextRank{}, &trank.KWTextRank{}, parser.Create(), WithPrevious(previous))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sums, err := doc.Summarize(2, 0, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, sum := range sums {
+		assert.Contains(t, sum.Raw, "bike lane")
+	}
+}
+
+func TestCompare(t *testing.T) {
+	p := parser.Create()
+	a, err := Create("The city council approved a new budget on Tuesday. The vote passed seven to two "+
+		"after a long debate.", &btrank.BiasedTextRank{}, &trank.KWTextRank{}, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := Create("On Tuesday the city council approved the new budget. Construction of the bike "+
+		"lanes is expected to begin in the spring.", &btrank.BiasedTextRank{}, &trank.KWTextRank{}, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cmp, err := Compare(a, b, 0.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if assert.Len(t, cmp.Shared, 1) {
+		assert.Equal(t, "On Tuesday the city council approved the new budget.", cmp.Shared[0].Match.Raw)
+		assert.GreaterOrEqual(t, cmp.Shared[0].Score, 0.5)
+	}
+	if assert.Len(t, cmp.OnlyA, 1) {
+		assert.Equal(t, "The vote passed seven to two after a long debate.", cmp.OnlyA[0].Sentence.Raw)
+	}
+	if assert.Len(t, cmp.OnlyB, 1) {
+		assert.Less(t, cmp.OnlyB[0].Score, 0.5)
+	}
+}
+
+func TestHierarchy(t *testing.T) {
+	text := strings.Join(readTestdata(t), "\n\n")
+	summarizers := func() basically.Summarizer { return &btrank.BiasedTextRank{} }
+
+	doc, err := Create(text, &btrank.BiasedTextRank{}, &trank.KWTextRank{}, parser.Create(), WithHierarchy(20, summarizers))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sums, err := doc.Summarize(5, 0, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Len(t, sums, 5)
+	for idx, sum := range sums {
+		assert.Equal(t, sum.Raw, strings.TrimSpace(text[sum.Start:sum.End]))
+		if idx > 0 {
+			assert.Less(t, sums[idx-1].Order, sum.Order)
+		}
+	}
+}
+
+func TestHierarchyReduces(t *testing.T) {
+	_, err := Create("One sentence.", &btrank.BiasedTextRank{}, &trank.KWTextRank{}, parser.Create(), WithHierarchy(2, nil))
+	assert.Error(t, err)
+
+	// Every sentence starts a section, so that chunks end early.
+	configs, err := configure([]Config{WithHierarchy(3, nil)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := &Document{Configs: configs, Summarizer: &btrank.BiasedTextRank{}}
+	for idx, word := range strings.Fields("vaccine dose trial ballot vote poll budget tax spending transit") {
+		doc.Sentences = append(doc.Sentences, &basically.Sentence{
+			Raw:     word,
+			Tokens:  []*basically.Token{{Tag: "NN", Text: word, Sentence: idx}},
+			Order:   idx,
+			Section: idx,
+		})
+	}
+
+	sents := doc.reduce(1, doc.ranking(nil, false))
+	assert.LessOrEqual(t, len(sents), 3)
+
+	// Chunks too small to reduce stop the reduction, rather than looping forever.
+	doc.Configs.chunk = 1
+	sents = doc.reduce(1, doc.ranking(nil, false))
+	assert.Len(t, sents, len(doc.Sentences))
+}
+
+func TestStream(t *testing.T) {
+	st, err := CreateStream(&btrank.BiasedTextRank{}, parser.Create(), 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msgs := []string{
+		"The deployment pipeline failed again this morning.",
+		"The staging cluster ran out of memory during the build.",
+		"Can someone raise the memory limit of the build agents?",
+		"I raised the memory limit, and restarted the pipeline.",
+		"The build passed, and the deployment to staging is done.",
+		"Lunch is at noon today.",
+	}
+	for _, msg := range msgs {
+		if err := st.Add(msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	assert.Len(t, st.Sentences, 4)
+	assert.Equal(t, 2, st.Sentences[0].Order)
+
+	sums := st.Summarize(2)
+	assert.Len(t, sums, 2)
+	for idx, sum := range sums {
+		assert.GreaterOrEqual(t, sum.Order, 2)
+		if idx > 0 {
+			assert.Less(t, sums[idx-1].Order, sum.Order)
+		}
+	}
+	assert.NotContains(t, []string{sums[0].Raw, sums[1].Raw}, "Lunch is at noon today.")
+
+	// Summaries leave the sentences of the stream whole.
+	st, err = CreateStream(&btrank.BiasedTextRank{}, parser.Create(), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msgs = []string{"But the build failed again on staging.", "And the deployment to staging was rolled back."}
+	for _, msg := range msgs {
+		if err := st.Add(msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		sums = st.Summarize(2)
+		assert.Equal(t, "The build failed again on staging.", sums[0].Raw)
+		assert.Equal(t, "The deployment to staging was rolled back.", sums[1].Raw)
+	}
+	assert.Equal(t, msgs, []string{st.Sentences[0].Raw, st.Sentences[1].Raw})
+}
+
+func TestCompression(t *testing.T) {
+	text := "However, the council approved the city budget on Monday. " +
+		"The budget raises spending on parks (and libraries) across the city. " +
+		"Council members debated the budget for hours."
+
+	doc, err := Create(text, &btrank.BiasedTextRank{}, &trank.KWTextRank{}, parser.Create(),
+		WithCompression(sentence.CreateCompressor()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for run := 0; run < 2; run++ {
+		sums, err := doc.Summarize(3, 0, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, "The council approved the city budget on Monday.", sums[0].Raw)
+		assert.Equal(t, "The budget raises spending on parks across the city.", sums[1].Raw)
+	}
+
+	// The document's sentences are left whole.
+	sents := doc.(*Document).Sentences
+	raws := make([]string, 0, len(sents))
+	for _, sent := range sents {
+		raws = append(raws, sent.Raw)
+	}
+	assert.Contains(t, raws, "However, the council approved the city budget on Monday.")
+	assert.Contains(t, raws, "The budget raises spending on parks (and libraries) across the city.")
+}
+
+func TestSections(t *testing.T) {
+	text := "# Budget\n\n" +
+		"The council approved the city budget on Monday. " +
+		"The budget raises spending on city parks and libraries. " +
+		"Council members debated the budget for hours.\n\n" +
+		"# Transit\n\n" +
+		"The council also voted on the city transit plan. " +
+		"The transit plan adds bus lanes and night trains. " +
+		"Riders have asked for more trains and buses for years.\n"
+
+	p := parser.Create(parser.WithMarkdown())
+	doc, err := Create(text, &btrank.BiasedTextRank{}, &trank.KWTextRank{}, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sums, err := doc.(*Document).SummarizeSections(1, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if assert.Len(t, sums, 2) {
+		assert.Equal(t, 0, sums[0][0].Section)
+		assert.Equal(t, 1, sums[1][0].Section)
+	}
+
+	kws, err := doc.(*Document).HighlightSections(3, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if assert.Len(t, kws, 2) {
+		words := func(kws []*basically.Keyword) []string {
+			ret := make([]string, 0, len(kws))
+			for _, kw := range kws {
+				ret = append(ret, strings.ToLower(kw.Word))
+			}
+			return ret
+		}
+		assert.Equal(t, "budget", words(kws[0])[0])
+		assert.Contains(t, words(kws[1]), "transit")
+		assert.NotContains(t, words(kws[1]), "city")
+	}
+}
+
+func TestSummarizeBudget(t *testing.T) {
+	p := parser.Create()
+	text := readTestdata(t)[0]
+	doc, err := Create(text, &btrank.BiasedTextRank{}, &trank.KWTextRank{}, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sums, err := doc.(*Document).SummarizeBudget(basically.Budget{Unit: basically.Words, Limit: 60}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var words int
+	for _, sum := range sums {
+		words += len(strings.Fields(sum.Raw))
+	}
+	assert.NotEmpty(t, sums)
+	assert.LessOrEqual(t, words, 60)
+
+	sums, err = doc.(*Document).SummarizeBudget(basically.Budget{Unit: basically.Sentences, Limit: 3}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, sums, 3)
+
+	_, err = doc.(*Document).SummarizeBudget(basically.Budget{Unit: basically.Unit(42), Limit: 3}, "")
+	assert.Error(t, err)
+}
+
+func TestKnapsack(t *testing.T) {
+	// A long sentence gives way to two shorter ones of greater total score.
+	long := &basically.Sentence{Raw: "one two three four five six", Score: 3}
+	short1 := &basically.Sentence{Raw: "one two three", Score: 2}
+	short2 := &basically.Sentence{Raw: "four five six", Score: 2}
+	sel, err := knapsack([]*basically.Sentence{long, short1, short2}, 6, basically.Words)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []*basically.Sentence{short1, short2}, sel)
+
+	// Everything is kept if it fits.
+	sel, err = knapsack([]*basically.Sentence{long, short1}, 100, basically.Characters)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []*basically.Sentence{long, short1}, sel)
+
+	_, err = knapsack([]*basically.Sentence{long}, 6, basically.Unit(42))
+	assert.Error(t, err)
+}
+
+func TestSummarizeThreshold(t *testing.T) {
+	p := parser.Create()
+	texts := readTestdata(t)
+
+	// Summaries do not depend on previous ones, and leave the document's sentences whole.
+	for _, text := range texts {
+		doc, err := Create(text, &btrank.BiasedTextRank{}, &trank.KWTextRank{}, p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		raws := make([]string, 0)
+		for _, sent := range doc.(*Document).Sentences {
+			raws = append(raws, sent.Raw)
+		}
+
+		summaries := make([][]basically.Sentence, 2)
+		for idx := range summaries {
+			sums, err := doc.Summarize(5, 0.1, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, sum := range sums {
+				summaries[idx] = append(summaries[idx], *sum)
+			}
+		}
+		assert.Equal(t, summaries[0], summaries[1])
+		for idx, sent := range doc.(*Document).Sentences {
+			assert.Equal(t, raws[idx], sent.Raw)
+		}
+	}
+
+	// The summary ends with the sentence crossing the threshold, which is its lowest ranked.
+	doc, err := Create(texts[0], &btrank.BiasedTextRank{}, &trank.KWTextRank{}, p, WithConjunctions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	sums, err := doc.Summarize(5, 0.1, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var chars int
+	lowest := sums[0]
+	for _, sum := range sums {
+		chars += utf8.RuneCountInString(sum.Raw)
+		if sum.Score < lowest.Score {
+			lowest = sum
+		}
+	}
+	orig, summ := doc.Characters()
+	assert.Equal(t, chars, summ)
+	if len(sums) < 5 {
+		last := utf8.RuneCountInString(lowest.Raw)
+		assert.Greater(t, float64(summ)/float64(orig), 0.1)
+		assert.LessOrEqual(t, float64(summ-last)/float64(orig), 0.1)
+	}
+}
+func TestKeywordWindow(t *testing.T) {
+	text := readTestdata(t)[0]
+	edges := func(window int) int {
+		h := &trank.KWTextRank{Weighting: trank.Uniform}
+		doc, err := Create(text, &btrank.BiasedTextRank{}, h, parser.Create(), WithKeywordWindow(window))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := doc.Highlight(5, false); err != nil {
+			t.Fatal(err)
+		}
+
+		var total int
+		for _, neighbours := range h.Graph.Edges {
+			for _, w := range neighbours {
+				total += int(w)
+			}
+		}
+		return total
+	}
+
+	// Larger windows add co-occurrences between words further apart.
+	assert.Less(t, edges(1), edges(2))
+	assert.Less(t, edges(2), edges(4))
+}
+
 func BenchmarkSummarize(b *testing.B) {
 	file, err := os.Open("../test")
 	if err != nil {
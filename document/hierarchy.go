@@ -0,0 +1,83 @@
+package document
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/algao1/basically"
+)
+
+// The minimum number of sentences of hierarchical chunks. Smaller chunks may hold a single
+// sentence each (when every sentence starts a section), and are never reduced.
+const minChunk = 3
+
+// reduce ranks the sentences of the document hierarchically, for a summary of the given length.
+// The sentences are split into chunks, and the top sentences of each chunk are kept, until the
+// remaining sentences fit in a single chunk. The remaining sentences are ranked, and returned.
+func (doc *Document) reduce(length int, r *ranking) []*basically.Sentence {
+	sents := ordered(doc.Sentences)
+	for len(sents) > doc.Configs.chunk {
+		chunks := chunks(sents, doc.Configs.chunk)
+		tops := make([][]*basically.Sentence, len(chunks))
+
+		var wg sync.WaitGroup
+		for idx, chunk := range chunks {
+			top := func(idx int, chunk []*basically.Sentence, s basically.Summarizer) {
+				r.rank(s, chunk)
+				sort.SliceStable(chunk, func(i, j int) bool { return chunk[i].Score > chunk[j].Score })
+
+				// Chunks are halved (rounding up), so that the sentences are eventually reduced to one chunk.
+				keep := length
+				if half := (len(chunk) + 1) / 2; keep > half {
+					keep = half
+				}
+				tops[idx] = chunk[:keep]
+			}
+
+			if doc.Configs.summarizers == nil {
+				top(idx, chunk, doc.Summarizer)
+				continue
+			}
+			wg.Add(1)
+			go func(idx int, chunk []*basically.Sentence) {
+				defer wg.Done()
+				top(idx, chunk, doc.Configs.summarizers())
+			}(idx, chunk)
+		}
+		wg.Wait()
+
+		kept := make([]*basically.Sentence, 0, len(sents))
+		for _, top := range tops {
+			kept = append(kept, ordered(top)...)
+		}
+
+		// Stops if no sentences were removed (when every chunk has a single sentence).
+		done := len(kept) == len(sents)
+		sents = kept
+		if done {
+			break
+		}
+	}
+
+	r.rank(doc.Summarizer, sents)
+	return sents
+}
+
+// chunks splits the sentences into consecutive chunks of at most size sentences. Chunks end with
+// a section, unless they would be less than half full.
+func chunks(sents []*basically.Sentence, size int) [][]*basically.Sentence {
+	chunks := make([][]*basically.Sentence, 0, len(sents)/size+1)
+	var cur []*basically.Sentence
+	for idx, sent := range sents {
+		section := idx > 0 && sent.Section != sents[idx-1].Section
+		if len(cur) == size || (section && 2*len(cur) >= size) {
+			chunks = append(chunks, cur)
+			cur = nil
+		}
+		cur = append(cur, sent)
+	}
+	if len(cur) > 0 {
+		chunks = append(chunks, cur)
+	}
+	return chunks
+}
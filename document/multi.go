@@ -0,0 +1,82 @@
+package document
+
+import (
+	"fmt"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/algao1/basically"
+)
+
+// The default redundancy threshold of multi-document summaries.
+const multiRedundancy = 0.5
+
+// CreateMulti creates a single document from several related texts (such as articles covering
+// the same story), so that their sentences are ranked together. Each sentence records the name
+// of its source, and sentences redundant with higher-ranked ones (often repeated across sources)
+// are removed from the summary, unless configured otherwise with WithRedundancy. The summary
+// focuses on the first sentences of every source by default.
+//
+// Sources are named by their index if no names are given. Sentence offsets (Start, End) refer
+// to the text of their source.
+func CreateMulti(texts, sources []string, s basically.Summarizer, h basically.Highlighter,
+	p basically.Parser, cfgs ...Config) (*Document, error) {
+	if sources == nil {
+		for i := range texts {
+			sources = append(sources, strconv.Itoa(i))
+		}
+	}
+	if len(sources) != len(texts) {
+		return nil, fmt.Errorf("got %d sources for %d texts", len(sources), len(texts))
+	}
+
+	configs, err := configure(append([]Config{WithRedundancy(multiRedundancy)}, cfgs...))
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &Document{
+		Configs:     configs,
+		Summarizer:  s,
+		Highlighter: h,
+		Parser:      p,
+		Sentences:   make([]*basically.Sentence, 0),
+		Words:       make([]*basically.Token, 0),
+	}
+
+	var paragraphs, sections int
+	for i, text := range texts {
+		sents, words, err := p.ParseDocument(text, configs.quotations)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", "unable to parse document "+sources[i], err)
+		}
+
+		// Renumbers the sentences and tokens to follow those of the previous sources.
+		var lastParagraph, lastSection int
+		for _, sent := range sents {
+			lastParagraph, lastSection = sent.Paragraph, sent.Section
+			sent.Order += len(doc.Sentences)
+			sent.Paragraph += paragraphs
+			sent.Section += sections
+			sent.Source = sources[i]
+		}
+		for _, word := range words {
+			word.Order += len(doc.Words)
+			word.Sentence += len(doc.Sentences)
+		}
+		if len(sents) > 0 {
+			paragraphs += lastParagraph + 1
+			sections += lastSection + 1
+		}
+
+		doc.Sentences = append(doc.Sentences, sents...)
+		doc.Words = append(doc.Words, words...)
+		doc.CharCount += utf8.RuneCountInString(text)
+	}
+	if err := doc.parsePrevious(); err != nil {
+		return nil, err
+	}
+	doc.segment()
+
+	return doc, nil
+}
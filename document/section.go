@@ -0,0 +1,109 @@
+package document
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/algao1/basically"
+)
+
+// The number of candidate keywords highlighted per section, for each keyword returned.
+const sectionCandidates = 3
+
+// SummarizeSections returns a summary of each section (started by a heading, or a topic boundary
+// if WithTopics is set), in order, of at most the given length. Each section is ranked on its own,
+// so that long sections do not crowd out the others.
+func (doc *Document) SummarizeSections(length int, threshold float64) ([][]*basically.Sentence, error) {
+	sections := doc.group(func(sent *basically.Sentence) int { return sent.Section })
+	doc.SummCount = 0
+
+	sums := make([][]*basically.Sentence, 0, len(sections))
+	for _, section := range sections {
+		n := length
+		if n > len(section.Sentences) {
+			n = len(section.Sentences)
+		}
+
+		sum, err := section.Summarize(n, threshold, "")
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", "unable to summarize section", err)
+		}
+		doc.SummCount += section.SummCount
+		sums = append(sums, sum)
+	}
+
+	return sums, nil
+}
+
+// HighlightSections returns the keywords of each section, in order, of at most the given length.
+// Keywords are ranked within their section, and weighted by the share of their occurrences in the
+// document which fall within the section, so that words frequent throughout the document give
+// way to those distinctive to the section.
+func (doc *Document) HighlightSections(length int, merge bool) ([][]*basically.Keyword, error) {
+	total := count(doc.Words)
+
+	kwords := make([][]*basically.Keyword, 0)
+	for _, section := range doc.group(func(sent *basically.Sentence) int { return sent.Section }) {
+		if len(section.Words) == 0 {
+			kwords = append(kwords, []*basically.Keyword{})
+			continue
+		}
+
+		// Highlighters fall back to fewer keywords if more are requested than the section has,
+		// in which case the section is highlighted again without extra candidates.
+		kws, err := section.Highlight(length*sectionCandidates, merge)
+		if err == nil && len(kws) < length {
+			kws, err = section.Highlight(length, merge)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", "unable to highlight section", err)
+		}
+
+		local := count(section.Words)
+		for _, kw := range kws {
+			kw.Weight *= share(kw.Word, local, total)
+		}
+		sort.SliceStable(kws, func(i, j int) bool {
+			if kws[i].Weight != kws[j].Weight {
+				return kws[i].Weight > kws[j].Weight
+			}
+			return kws[i].Word < kws[j].Word
+		})
+
+		if length < len(kws) {
+			kws = kws[:length]
+		}
+		kwords = append(kwords, kws)
+	}
+
+	return kwords, nil
+}
+
+// count counts the (lowercased) words of the tokens.
+func count(tokens []*basically.Token) map[string]int {
+	counts := make(map[string]int)
+	for _, tok := range tokens {
+		for _, word := range strings.Fields(strings.ToLower(tok.Text)) {
+			counts[word]++
+		}
+	}
+	return counts
+}
+
+// share returns the average share of occurrences of the keyword's words found locally.
+func share(keyword string, local, total map[string]int) float64 {
+	var sum float64
+	var n int
+	for _, word := range strings.Fields(strings.ToLower(keyword)) {
+		if total[word] > 0 {
+			sum += float64(local[word]) / float64(total[word])
+			n++
+		}
+	}
+
+	if n == 0 {
+		return 1
+	}
+	return sum / float64(n)
+}
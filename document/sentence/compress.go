@@ -0,0 +1,315 @@
+package sentence
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/algao1/basically"
+)
+
+// A Compressor shortens sentences by removing the parts which rarely carry their main point,
+// using their POS tags and punctuation. Each rule may be disabled.
+type Compressor struct {
+	Parentheticals bool // Removes text within parentheses, brackets, or a pair of dashes.
+	Appositives    bool // Removes appositives, as in "Smith, the minister, said".
+	Attributions   bool // Removes attribution clauses, as in "..., the minister said on Tuesday.".
+	Markers        bool // Removes leading discourse markers, as in "However, ...".
+	Relatives      bool // Removes trailing relative clauses, as in "..., which ...".
+}
+
+// Discourse markers removed from the beginning of sentences (when followed by a comma).
+var markers = [][]string{
+	{"however"}, {"meanwhile"}, {"moreover"}, {"furthermore"}, {"additionally"},
+	{"nevertheless"}, {"nonetheless"}, {"indeed"}, {"therefore"}, {"thus"},
+	{"consequently"}, {"besides"}, {"likewise"}, {"similarly"}, {"still"}, {"also"},
+	{"in", "addition"}, {"in", "fact"}, {"of", "course"}, {"as", "a", "result"},
+	{"on", "the", "other", "hand"}, {"at", "the", "same", "time"},
+}
+
+// Reporting verbs of attribution clauses.
+var reporting = map[string]struct{}{
+	"said": {}, "says": {}, "say": {}, "added": {}, "adds": {}, "told": {}, "tells": {},
+	"noted": {}, "notes": {}, "explained": {}, "stated": {}, "wrote": {}, "writes": {},
+	"announced": {}, "reported": {}, "argued": {}, "claimed": {}, "according": {},
+}
+
+// The maximum number of tokens of a removed appositive or attribution clause.
+const clauseTokens = 10
+
+// CreateCompressor creates a Compressor with every rule enabled.
+func CreateCompressor() *Compressor {
+	return &Compressor{
+		Parentheticals: true,
+		Appositives:    true,
+		Attributions:   true,
+		Markers:        true,
+		Relatives:      true,
+	}
+}
+
+// Compress applies the enabled rules to the sentence, removing text from Raw along with its tokens.
+// Only removals from the beginning of the sentence move Start (if Raw matches the original text).
+// Other removals keep the final punctuation, so [Start, End) still spans the remaining text, along
+// with the interior text that was removed. Sentences whose tokens cannot be located within Raw are
+// left unchanged. The sentence is modified in place, so shared sentences should be copied first.
+func (c *Compressor) Compress(s *basically.Sentence) {
+	rules := []struct {
+		enabled bool
+		find    func(toks []*basically.Token) (int, int)
+	}{
+		{c.Markers, marker},
+		{c.Attributions, attribution},
+		{c.Parentheticals, parenthetical},
+		{c.Appositives, appositive},
+		{c.Relatives, relative},
+	}
+
+	// Start can only be moved while Raw matches the original text byte for byte.
+	exact := s.End-s.Start == len(s.Raw)
+	for _, rule := range rules {
+		if !rule.enabled {
+			continue
+		}
+
+		// Rules may apply several times, such as to every parenthetical.
+		for {
+			from, to := rule.find(s.Tokens)
+			if from >= to || !remove(s, from, to, exact) {
+				break
+			}
+		}
+	}
+}
+
+// remove removes the tokens [from, to) and their text from the sentence, along with the
+// whitespace preceding them (or following them, at the beginning of the sentence).
+func remove(s *basically.Sentence, from, to int, exact bool) bool {
+	spans, ok := locate(s)
+	if !ok {
+		return false
+	}
+
+	start, end := spans[from][0], spans[to-1][1]
+	for start > 0 {
+		r, size := utf8.DecodeLastRuneInString(s.Raw[:start])
+		if !unicode.IsSpace(r) {
+			break
+		}
+		start -= size
+	}
+	if start == 0 {
+		for end < len(s.Raw) {
+			r, size := utf8.DecodeRuneInString(s.Raw[end:])
+			if !unicode.IsSpace(r) {
+				break
+			}
+			end += size
+		}
+	}
+
+	capital, _ := utf8.DecodeRuneInString(s.Raw)
+	s.Raw = s.Raw[:start] + s.Raw[end:]
+	if start == 0 {
+		// The new first word is capitalized in place of the removed one, unless it has
+		// capitals of its own (such as "iPhone").
+		first := strings.Fields(s.Raw)
+		if unicode.IsUpper(capital) && len(first) > 0 && first[0] == strings.ToLower(first[0]) {
+			s.Raw = Capitalize(s.Raw)
+		}
+		if exact {
+			s.Start += end
+		}
+	}
+	s.Tokens = append(append([]*basically.Token(nil), s.Tokens[:from]...), s.Tokens[to:]...)
+	return true
+}
+
+// locate finds the byte span of every token within the raw sentence.
+func locate(s *basically.Sentence) ([][2]int, bool) {
+	lower := strings.ToLower(s.Raw)
+	if len(lower) != len(s.Raw) {
+		return nil, false
+	}
+	spans := make([][2]int, 0, len(s.Tokens))
+
+	pos := 0
+	for _, tok := range s.Tokens {
+		idx := strings.Index(lower[pos:], strings.ToLower(tok.Text))
+		if idx < 0 {
+			return nil, false
+		}
+		pos += idx
+		spans = append(spans, [2]int{pos, pos + len(tok.Text)})
+		pos += len(tok.Text)
+	}
+	return spans, true
+}
+
+// body returns the index of the sentence's final punctuation (or the number of tokens).
+func body(toks []*basically.Token) int {
+	last := len(toks)
+	for last > 0 && (toks[last-1].Tag == "." || isQuote(toks[last-1].Text)) {
+		last--
+	}
+	return last
+}
+
+// clause determines if the tokens contain a verb, and so form a clause of their own.
+func clause(toks []*basically.Token) bool {
+	for _, tok := range toks {
+		if IsVerb(tok.Tag) || tok.Tag == "MD" {
+			return true
+		}
+	}
+	return false
+}
+
+// marker finds a leading discourse marker, followed by a comma.
+func marker(toks []*basically.Token) (int, int) {
+	for _, words := range markers {
+		n := len(words)
+		if len(toks) <= n+1 || toks[n].Text != "," {
+			continue
+		}
+
+		match := true
+		for idx, word := range words {
+			match = match && strings.ToLower(toks[idx].Text) == word
+		}
+		if match && clause(toks[n+1:]) {
+			return 0, n + 1
+		}
+	}
+	return 0, 0
+}
+
+// attribution finds a trailing attribution clause (", the minister said on Tuesday"),
+// or a leading one ("According to the minister, ").
+func attribution(toks []*basically.Token) (int, int) {
+	if len(toks) > 3 && strings.ToLower(toks[0].Text) == "according" && toks[1].Text == "to" {
+		for idx := 2; idx < len(toks) && idx <= clauseTokens; idx++ {
+			if toks[idx].Text == "," {
+				if clause(toks[idx+1:]) {
+					return 0, idx + 1
+				}
+				break
+			}
+		}
+	}
+
+	last := body(toks)
+	for idx := last - 2; idx >= 3 && last-idx <= clauseTokens; idx-- {
+		if toks[idx].Text != "," {
+			continue
+		}
+		// Quotations are left whole, as they need their attribution.
+		if isQuote(toks[idx+1].Text) || !clause(toks[:idx]) {
+			break
+		}
+
+		// Attribution clauses have no verbs other than the reporting verb.
+		reported := false
+		for _, tok := range toks[idx+1 : last] {
+			_, ok := reporting[strings.ToLower(tok.Text)]
+			if !ok && (IsVerb(tok.Tag) || tok.Tag == "MD") {
+				return 0, 0
+			}
+			reported = reported || ok
+		}
+		if reported {
+			return idx, last
+		}
+		break
+	}
+	return 0, 0
+}
+
+// parenthetical finds text within parentheses, brackets, or a pair of dashes.
+func parenthetical(toks []*basically.Token) (int, int) {
+	closing := map[string]string{"(": ")", "[": "]", "—": "—", "–": "–", "--": "--"}
+	for from, tok := range toks {
+		end, ok := closing[tok.Text]
+		if !ok {
+			continue
+		}
+
+		for to := from + 1; to < len(toks); to++ {
+			if toks[to].Text == end {
+				// Parentheticals are only removed if a sentence remains.
+				rest := append(append([]*basically.Token(nil), toks[:from]...), toks[to+1:]...)
+				if clause(rest) {
+					return from, to + 1
+				}
+				break
+			}
+		}
+	}
+	return 0, 0
+}
+
+// appositive finds an appositive noun phrase following a proper noun, between commas
+// (or a comma and the final punctuation).
+func appositive(toks []*basically.Token) (int, int) {
+	last := body(toks)
+	for idx := 0; idx+2 < last; idx++ {
+		if !IsProperNoun(toks[idx].Tag) || toks[idx+1].Text != "," {
+			continue
+		}
+
+		// Appositives start with a determiner, adjective or common noun (so that lists of
+		// proper nouns are left alone), and contain a noun but no verb.
+		switch toks[idx+2].Tag {
+		case "DT", "PRP$", "JJ", "JJS", "CD", "NN", "NNS":
+		default:
+			continue
+		}
+
+		for end := idx + 2; end <= last && end-idx <= clauseTokens; end++ {
+			if end < last && toks[end].Text != "," {
+				continue
+			}
+
+			phrase := toks[idx+2 : end]
+			noun := false
+			for _, tok := range phrase {
+				noun = noun || IsNoun(tok.Tag)
+			}
+			if noun && !clause(phrase) && clause(append(append([]*basically.Token(nil), toks[:idx+1]...), toks[end:]...)) {
+				if end < last {
+					end++
+				}
+				return idx + 1, end
+			}
+			break
+		}
+	}
+	return 0, 0
+}
+
+// relative finds a trailing (non-restrictive) relative clause, following a comma.
+func relative(toks []*basically.Token) (int, int) {
+	last := body(toks)
+	for idx := 2; idx+1 < last; idx++ {
+		if toks[idx].Text != "," {
+			continue
+		}
+
+		switch toks[idx+1].Tag {
+		case "WDT", "WP", "WP$":
+			if clause(toks[:idx]) {
+				return idx, last
+			}
+		}
+	}
+	return 0, 0
+}
+
+// isQuote determines if the text is a quotation mark.
+func isQuote(text string) bool {
+	switch text {
+	case "\"", "“", "”", "``", "''", "'", "‘", "’":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+package sentence
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/algao1/basically"
+	"github.com/stretchr/testify/assert"
+)
+
+// tagged creates a sentence from space-separated word/TAG pairs.
+func tagged(raw, pairs string) *basically.Sentence {
+	s := &basically.Sentence{Raw: raw, Start: 10, End: 10 + len(raw)}
+	for _, pair := range strings.Fields(pairs) {
+		idx := strings.LastIndex(pair, "/")
+		s.Tokens = append(s.Tokens, &basically.Token{Text: strings.ToLower(pair[:idx]), Tag: pair[idx+1:]})
+	}
+	return s
+}
+
+func TestCompress(t *testing.T) {
+	cases := []struct {
+		raw, pairs, compressed string
+	}{
+		{
+			"However, prices rose sharply.",
+			"However/RB ,/, prices/NNS rose/VBD sharply/RB ./.",
+			"Prices rose sharply.",
+		},
+		{
+			"Prices will rise next year, the minister said on Tuesday.",
+			"Prices/NNS will/MD rise/VB next/JJ year/NN ,/, the/DT minister/NN said/VBD on/IN Tuesday/NNP ./.",
+			"Prices will rise next year.",
+		},
+		{
+			"Prices will rise (by 5%) next year.",
+			"Prices/NNS will/MD rise/VB (/( by/IN 5/CD %/NN )/) next/JJ year/NN ./.",
+			"Prices will rise next year.",
+		},
+		{
+			"Smith, the finance minister, announced the budget.",
+			"Smith/NNP ,/, the/DT finance/NN minister/NN ,/, announced/VBD the/DT budget/NN ./.",
+			"Smith announced the budget.",
+		},
+		{
+			"The council approved the budget, which raises taxes.",
+			"The/DT council/NN approved/VBD the/DT budget/NN ,/, which/WDT raises/VBZ taxes/NNS ./.",
+			"The council approved the budget.",
+		},
+		{
+			"If prices rise, we said we would act.",
+			"If/IN prices/NNS rise/VBP ,/, we/PRP said/VBD we/PRP would/MD act/VB ./.",
+			"If prices rise, we said we would act.",
+		},
+		{
+			"He ran away fast,",
+			"He/PRP ran/VBD away/RB fast/RB ,/,",
+			"He ran away fast,",
+		},
+		{
+			"Paris, London, and Rome were chosen.",
+			"Paris/NNP ,/, London/NNP ,/, and/CC Rome/NNP were/VBD chosen/VBN ./.",
+			"Paris, London, and Rome were chosen.",
+		},
+	}
+
+	c := CreateCompressor()
+	for _, tc := range cases {
+		s := tagged(tc.raw, tc.pairs)
+		c.Compress(s)
+		assert.Equal(t, tc.compressed, s.Raw, tc.raw)
+	}
+}
+
+func TestCompressText(t *testing.T) {
+	cases := []struct {
+		raw, pairs, compressed string
+	}{
+		{
+			"He said voilà (loudly) and left.",
+			"He/PRP said/VBD voilà/NN (/( loudly/RB )/) and/CC left/VBD ./.",
+			"He said voilà and left.",
+		},
+		{
+			"However, iPhone sales rose.",
+			"However/RB ,/, iPhone/NNP sales/NNS rose/VBD ./.",
+			"iPhone sales rose.",
+		},
+		{
+			"however, prices rose.",
+			"however/RB ,/, prices/NNS rose/VBD ./.",
+			"prices rose.",
+		},
+	}
+
+	c := CreateCompressor()
+	for _, tc := range cases {
+		s := tagged(tc.raw, tc.pairs)
+		c.Compress(s)
+		assert.Equal(t, tc.compressed, s.Raw, tc.raw)
+		assert.True(t, utf8.ValidString(s.Raw), tc.raw)
+	}
+
+	// Leading removals move Start, even after interior removals.
+	s := tagged("However, prices (in euros) rose.", "However/RB ,/, prices/NNS (/( in/IN euros/NNS )/) rose/VBD ./.")
+	remove(s, 3, 7, true)
+	remove(s, 0, 2, true)
+	assert.Equal(t, "Prices rose.", s.Raw)
+	assert.Equal(t, 19, s.Start)
+
+	// Removals at the beginning move the sentence's start.
+	s = tagged("Meanwhile, the vote was delayed.", "Meanwhile/RB ,/, the/DT vote/NN was/VBD delayed/VBN ./.")
+	c.Compress(s)
+	assert.Equal(t, 21, s.Start)
+	assert.Equal(t, "the", s.Tokens[0].Text)
+
+	// Rules may be disabled.
+	s = tagged("However, prices rose sharply.", "However/RB ,/, prices/NNS rose/VBD sharply/RB ./.")
+	(&Compressor{}).Compress(s)
+	assert.Equal(t, "However, prices rose sharply.", s.Raw)
+}
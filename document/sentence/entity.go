@@ -0,0 +1,129 @@
+package sentence
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/algao1/basically"
+)
+
+// An Alias is a sequence of (lowercased) words referring to a named-entity.
+type Alias struct {
+	Words  []string // The alias' words.
+	Proper bool     // Whether the alias only matches proper nouns.
+}
+
+// EntityAliases returns the aliases that refer to the named-entity `name`. These include the
+// name itself, the given aliases, and any entities in the document that contain the name,
+// abbreviate it (e.g. "WHO"), or refer to a person by part of their name (e.g. "Merkel" for
+// "Angela Merkel"). Aliases found from entities only match proper nouns.
+func EntityAliases(name string, aliases []string, ents []*basically.Entity) []Alias {
+	seen := make(map[string]struct{})
+	ret := make([]Alias, 0, len(aliases)+1)
+	add := func(seq []string, proper bool) {
+		key := strings.Join(seq, " ")
+		if _, ok := seen[key]; ok || len(seq) == 0 {
+			return
+		}
+		seen[key] = struct{}{}
+		ret = append(ret, Alias{Words: seq, Proper: proper})
+	}
+
+	focus := fields(name)
+	add(focus, false)
+	for _, alias := range aliases {
+		add(fields(alias), false)
+	}
+
+	acronym := Acronym(name)
+	for _, ent := range ents {
+		seq := make([]string, 0, len(ent.Tokens))
+		for _, tok := range ent.Tokens {
+			seq = append(seq, tok.Text)
+		}
+
+		switch {
+		case indexSeq(seq, focus) >= 0:
+			add(seq, true)
+		case ent.Label == "PERSON" && indexSeq(focus, seq) >= 0:
+			add(seq, true)
+		case len(acronym) > 1 && strings.Join(seq, "") == acronym:
+			add(seq, true)
+		}
+	}
+
+	return ret
+}
+
+// Mentions counts the non-overlapping occurrences of any of the aliases within the sentence.
+// Longer aliases take precedence over shorter ones.
+func Mentions(s *basically.Sentence, aliases []Alias) int {
+	words := make([]string, 0, len(s.Tokens))
+	for _, tok := range s.Tokens {
+		words = append(words, tok.Text)
+	}
+
+	count := 0
+	for i := 0; i < len(words); {
+		longest := 0
+		for _, alias := range aliases {
+			n := len(alias.Words)
+			if n <= longest || !hasPrefixSeq(words[i:], alias.Words) {
+				continue
+			}
+			if alias.Proper && !IsProperNoun(s.Tokens[i].Tag) && s.Tokens[i].Entity == "" {
+				continue
+			}
+			longest = n
+		}
+
+		if longest > 0 {
+			count++
+			i += longest
+		} else {
+			i++
+		}
+	}
+
+	return count
+}
+
+// Acronym returns the (lowercased) initials of the capitalized words in the string.
+func Acronym(str string) string {
+	var initials []rune
+	for _, word := range strings.Fields(str) {
+		r := []rune(word)[0]
+		if unicode.IsUpper(r) {
+			initials = append(initials, unicode.ToLower(r))
+		}
+	}
+	return string(initials)
+}
+
+// fields splits the string into lowercased words.
+func fields(str string) []string {
+	return strings.Fields(strings.ToLower(str))
+}
+
+// indexSeq returns the index of the first occurrence of sub in seq, or -1 if not present.
+func indexSeq(seq, sub []string) int {
+	for i := 0; i+len(sub) <= len(seq); i++ {
+		if hasPrefixSeq(seq[i:], sub) {
+			return i
+		}
+	}
+	return -1
+}
+
+// hasPrefixSeq determines if seq begins with prefix.
+func hasPrefixSeq(seq, prefix []string) bool {
+	if len(prefix) == 0 || len(prefix) > len(seq) {
+		return false
+	}
+	for i := range prefix {
+		if seq[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
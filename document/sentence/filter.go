@@ -53,6 +53,10 @@ func IsNoun(tag string) bool {
 	return tag == "NN" || tag == "NNP" || tag == "NNPS" || tag == "NNS"
 }
 
+func IsProperNoun(tag string) bool {
+	return tag == "NNP" || tag == "NNPS"
+}
+
 func IsVerb(tag string) bool {
 	return tag == "VB" || tag == "VBD" || tag == "VBG" ||
 		tag == "VBN" || tag == "VBP" || tag == "VBZ" || tag == "MD"
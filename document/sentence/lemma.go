@@ -0,0 +1,211 @@
+package sentence
+
+import (
+	"strings"
+
+	"github.com/algao1/basically"
+	"github.com/surgebase/porter2"
+)
+
+// Irregular inflections, keyed by their inflected form.
+var (
+	irregularNouns = map[string]string{
+		"children": "child", "men": "man", "women": "woman", "people": "person",
+		"mice": "mouse", "geese": "goose", "feet": "foot", "teeth": "tooth",
+		"lives": "life", "wives": "wife", "knives": "knife", "leaves": "leaf",
+		"halves": "half", "selves": "self", "shelves": "shelf", "wolves": "wolf",
+		"analyses": "analysis", "crises": "crisis", "theses": "thesis", "bases": "basis",
+		"diagnoses": "diagnosis", "hypotheses": "hypothesis", "criteria": "criterion",
+		"phenomena": "phenomenon", "indices": "index", "matrices": "matrix",
+		"vertices": "vertex", "appendices": "appendix", "stimuli": "stimulus",
+		"nuclei": "nucleus", "fungi": "fungus", "cacti": "cactus", "bacteria": "bacterium",
+		"media": "medium", "curricula": "curriculum", "series": "series", "species": "species",
+	}
+
+	irregularVerbs = map[string]string{
+		"am": "be", "is": "be", "are": "be", "was": "be", "were": "be", "been": "be", "being": "be",
+		"has": "have", "had": "have", "having": "have", "does": "do", "did": "do", "done": "do",
+		"went": "go", "gone": "go", "goes": "go", "made": "make", "said": "say", "says": "say",
+		"took": "take", "taken": "take", "got": "get", "gotten": "get", "saw": "see", "seen": "see",
+		"came": "come", "knew": "know", "known": "know", "gave": "give", "given": "give",
+		"found": "find", "thought": "think", "told": "tell", "became": "become", "left": "leave",
+		"felt": "feel", "brought": "bring", "began": "begin", "begun": "begin", "kept": "keep",
+		"held": "hold", "wrote": "write", "written": "write", "stood": "stand", "heard": "hear",
+		"meant": "mean", "met": "meet", "ran": "run", "paid": "pay", "sat": "sit", "spoke": "speak",
+		"spoken": "speak", "led": "lead", "grew": "grow", "grown": "grow", "lost": "lose",
+		"fell": "fall", "fallen": "fall", "sent": "send", "built": "build", "understood": "understand",
+		"drew": "draw", "drawn": "draw", "broke": "break", "broken": "break", "spent": "spend",
+		"rose": "rise", "risen": "rise", "drove": "drive", "driven": "drive", "bought": "buy",
+		"wore": "wear", "worn": "wear", "chose": "choose", "chosen": "choose", "sought": "seek",
+		"taught": "teach", "caught": "catch", "fought": "fight", "ate": "eat", "eaten": "eat",
+		"sold": "sell", "won": "win", "flew": "fly", "flown": "fly", "threw": "throw",
+		"thrown": "throw", "shown": "show", "forgot": "forget", "forgotten": "forget",
+		"hidden": "hide", "hid": "hide", "laid": "lay", "lay": "lie", "lain": "lie",
+		"struck": "strike", "swore": "swear", "sworn": "swear", "woke": "wake", "woken": "wake",
+		"doing": "do", "going": "go", "dying": "die", "died": "die", "lying": "lie", "lied": "lie",
+		"tying": "tie", "tied": "tie",
+	}
+
+	irregularAdjs = map[string]string{
+		"better": "good", "best": "good", "worse": "bad", "worst": "bad",
+		"more": "many", "most": "many", "less": "little", "least": "little",
+		"further": "far", "furthest": "far", "farther": "far", "farthest": "far",
+		"elder": "old", "eldest": "old",
+	}
+)
+
+// Stems ending in "s" that do not take a silent "e", and whose plurals end in "ses"
+// (e.g. "focused" -> "focus", "viruses" -> "virus").
+var bareS = map[string]struct{}{
+	"focus": {}, "bias": {}, "gas": {}, "bus": {}, "canvas": {}, "atlas": {}, "virus": {},
+	"bonus": {}, "campus": {}, "status": {}, "census": {}, "corpus": {}, "chorus": {},
+	"circus": {}, "consensus": {}, "surplus": {}, "alias": {}, "lens": {}, "iris": {},
+}
+
+// Lemmatize returns the dictionary form of the token's text, using its part-of-speech tag
+// to choose between noun, verb and adjective inflections (e.g. "vaccines" -> "vaccine",
+// "went" -> "go", "larger" -> "large"). Proper nouns and other tags are left unchanged.
+// Irregular forms are looked up in a small dictionary, and regular forms are handled by rules.
+func Lemmatize(tok *basically.Token) string {
+	word := strings.ToLower(tok.Text)
+	switch tok.Tag {
+	case "NNS":
+		if lemma, ok := irregularNouns[word]; ok {
+			return lemma
+		}
+		return singular(word)
+	case "VBD", "VBG", "VBN", "VBZ", "VBP":
+		if lemma, ok := irregularVerbs[word]; ok {
+			return lemma
+		}
+		switch {
+		case tok.Tag == "VBZ":
+			return singular(word)
+		case tok.Tag == "VBG" && strings.HasSuffix(word, "ing"):
+			return restore(word, word[:len(word)-3])
+		case strings.HasSuffix(word, "ied") && len(word) > 4:
+			return word[:len(word)-3] + "y"
+		case strings.HasSuffix(word, "ed"):
+			return restore(word, word[:len(word)-2])
+		}
+	case "JJR", "JJS":
+		if lemma, ok := irregularAdjs[word]; ok {
+			return lemma
+		}
+		suffix := "er"
+		if tok.Tag == "JJS" {
+			suffix = "est"
+		}
+		switch {
+		case strings.HasSuffix(word, "i"+suffix) && len(word) > len(suffix)+2:
+			return word[:len(word)-len(suffix)-1] + "y"
+		case strings.HasSuffix(word, suffix):
+			return restore(word, word[:len(word)-len(suffix)])
+		}
+	}
+	return word
+}
+
+// Stem returns the Porter2 stem of each word in the token's text.
+func Stem(tok *basically.Token) string {
+	words := fields(tok.Text)
+	for i, word := range words {
+		words[i] = porter2.Stem(word)
+	}
+	return strings.Join(words, " ")
+}
+
+// singular removes the plural (or third-person) suffix of a regular word.
+func singular(word string) string {
+	switch {
+	case len(word) <= 3, strings.HasSuffix(word, "ss"), strings.HasSuffix(word, "us"),
+		strings.HasSuffix(word, "is"):
+		return word
+	case strings.HasSuffix(word, "ses") && isBareS(word[:len(word)-2]):
+		return word[:len(word)-2]
+	case strings.HasSuffix(word, "ies") && len(word) > 4:
+		return word[:len(word)-3] + "y"
+	case strings.HasSuffix(word, "sses"), strings.HasSuffix(word, "xes"), strings.HasSuffix(word, "zes"),
+		strings.HasSuffix(word, "ches"), strings.HasSuffix(word, "shes"):
+		return word[:len(word)-2]
+	case strings.HasSuffix(word, "s"):
+		return word[:len(word)-1]
+	}
+	return word
+}
+
+// restore repairs the stem left after removing a suffix from the word, by undoubling a final
+// consonant (e.g. "stopp" -> "stop", "cancell" -> "cancel") or restoring a silent "e" (e.g.
+// "mak" -> "make").
+// The word is returned unchanged if the stem has no vowels (e.g. "shed" -> "sh").
+func restore(word, stem string) string {
+	if !strings.ContainsAny(stem, "aeiouy") {
+		return word
+	}
+
+	n := len(stem)
+	last := stem[n-1]
+	switch {
+	case n > 3 && last == stem[n-2] && !strings.ContainsRune("aeioulsfz", rune(last)):
+		return stem[:n-1]
+	case n > 3 && last == 'l' && stem[n-2] == 'l' && strings.ContainsRune("eou", rune(stem[n-3])) && measure(stem) > 1:
+		// Verbs of several syllables in "-el", "-ol" or "-ul" (e.g. "cancell", "controll"), but not "install".
+		return stem[:n-1]
+	case n > 1 && last == stem[n-2]:
+		return stem
+	}
+
+	var silent bool
+	switch {
+	case last == 'e', last == 'u', last == 'v', last == 'c', last == 'z':
+		silent = true
+	case last == 's':
+		silent = !isBareS(stem)
+	case last == 'g':
+		silent = strings.HasSuffix(stem, "rg") || strings.HasSuffix(stem, "dg") ||
+			(n > 4 && (strings.HasSuffix(stem, "ang") || strings.HasSuffix(stem, "eng")))
+	case last == 'l':
+		silent = n > 2 && !isVowel(stem[n-2])
+	case last == 't':
+		// Verbs in "-ate" (e.g. "locat", "initiat", "evaluat", "creat"), but not "treat" or "beat".
+		silent = n > 3 && strings.HasSuffix(stem, "at") &&
+			(!isVowel(stem[n-3]) || strings.ContainsRune("iu", rune(stem[n-3])) || strings.HasSuffix(stem, "creat"))
+	case last == 'd':
+		silent = n > 3 && strings.ContainsRune("iu", rune(stem[n-2])) && !isVowel(stem[n-3])
+	case last == 'r':
+		silent = n > 3 && strings.ContainsRune("aiu", rune(stem[n-2])) && !strings.ContainsRune("aeo", rune(stem[n-3]))
+	}
+	if silent || (measure(stem) == 1 && cvc(stem)) {
+		return stem + "e"
+	}
+	return stem
+}
+
+// measure counts the vowel-consonant sequences in the word.
+func measure(word string) int {
+	m := 0
+	for i := 1; i < len(word); i++ {
+		if isVowel(word[i-1]) && !isVowel(word[i]) {
+			m++
+		}
+	}
+	return m
+}
+
+// cvc determines if the word ends with a consonant-vowel-consonant sequence,
+// where the final consonant is not w, x or y (e.g. "hop", but not "show").
+func cvc(word string) bool {
+	n := len(word)
+	return n >= 3 && !isVowel(word[n-3]) && isVowel(word[n-2]) && !isVowel(word[n-1]) &&
+		!strings.ContainsRune("wxy", rune(word[n-1]))
+}
+
+// isBareS determines if the stem ends in an "s" that takes no silent "e".
+func isBareS(stem string) bool {
+	_, ok := bareS[stem]
+	return ok
+}
+
+func isVowel(b byte) bool {
+	return strings.IndexByte("aeiou", b) >= 0
+}
@@ -0,0 +1,50 @@
+package sentence
+
+import (
+	"testing"
+
+	"github.com/algao1/basically"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLemmatize(t *testing.T) {
+	cases := []struct {
+		text, tag, lemma string
+	}{
+		{"vaccines", "NNS", "vaccine"},
+		{"studies", "NNS", "study"},
+		{"churches", "NNS", "church"},
+		{"children", "NNS", "child"},
+		{"vaccinated", "VBN", "vaccinate"},
+		{"stopped", "VBD", "stop"},
+		{"making", "VBG", "make"},
+		{"needed", "VBD", "need"},
+		{"went", "VBD", "go"},
+		{"includes", "VBZ", "include"},
+		{"larger", "JJR", "large"},
+		{"biggest", "JJS", "big"},
+		{"better", "JJR", "good"},
+		{"Americans", "NNPS", "americans"},
+		{"created", "VBD", "create"},
+		{"treated", "VBD", "treat"},
+		{"evaluated", "VBN", "evaluate"},
+		{"initiated", "VBN", "initiate"},
+		{"viruses", "NNS", "virus"},
+		{"buses", "NNS", "bus"},
+		{"causes", "NNS", "cause"},
+		{"houses", "NNS", "house"},
+		{"focuses", "VBZ", "focus"},
+		{"controlled", "VBD", "control"},
+		{"cancelled", "VBN", "cancel"},
+		{"travelling", "VBG", "travel"},
+		{"annulled", "VBD", "annul"},
+		{"installed", "VBD", "install"},
+		{"called", "VBD", "call"},
+		{"filled", "VBD", "fill"},
+		{"smelled", "VBD", "smell"},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.lemma, Lemmatize(&basically.Token{Text: c.text, Tag: c.tag}), c.text)
+	}
+}
@@ -0,0 +1,121 @@
+package sentence
+
+import (
+	"math"
+
+	"github.com/algao1/basically"
+)
+
+// A Boundary is a topic boundary between two sentences.
+type Boundary struct {
+	Sentence int     // The index of the first sentence following the boundary.
+	Depth    float64 // How sharply the lexical cohesion drops at the boundary.
+}
+
+// TextTiling segments the sentences into topics by their lexical cohesion, following Hearst (1997),
+// "TextTiling: Segmenting Text into Multi-paragraph Subtopic Passages". Every gap between two
+// sentences is scored by the cosine similarity of the blocks of (at most) block sentences on either
+// side, using the normalized tokens which pass the filter. The depth of a gap is how far its score
+// lies below the highest scores reached on each side, before they start to fall again. Gaps which are
+// local minima, with depths of at least the mean depth minus half its standard deviation,
+// are returned as boundaries, in order.
+func TextTiling(sents []*basically.Sentence, filter basically.TokenFilter, block int) []Boundary {
+	if len(sents) < 2 || block < 1 {
+		return nil
+	}
+
+	// Counts the normalized tokens of each sentence.
+	counts := make([]map[string]float64, len(sents))
+	for idx, sent := range sents {
+		counts[idx] = make(map[string]float64)
+		for _, tok := range sent.Tokens {
+			if filter == nil || filter(tok) {
+				counts[idx][normalize(tok)]++
+			}
+		}
+	}
+
+	// Scores the gap before each sentence (gaps[0] is unused).
+	gaps := make([]float64, len(sents))
+	for i := 1; i < len(sents); i++ {
+		left := make(map[string]float64)
+		right := make(map[string]float64)
+		for j := 0; j < block; j++ {
+			if i-1-j >= 0 {
+				add(left, counts[i-1-j])
+			}
+			if i+j < len(sents) {
+				add(right, counts[i+j])
+			}
+		}
+		gaps[i] = cosine(left, right)
+	}
+
+	// Computes the depth of every local minimum.
+	depths := make([]float64, len(sents))
+	var minima []int
+	for i := 1; i < len(sents); i++ {
+		if (i > 1 && gaps[i-1] < gaps[i]) || (i+1 < len(sents) && gaps[i+1] < gaps[i]) {
+			continue
+		}
+
+		lpeak, rpeak := gaps[i], gaps[i]
+		for j := i - 1; j >= 1 && gaps[j] >= lpeak; j-- {
+			lpeak = gaps[j]
+		}
+		for j := i + 1; j < len(sents) && gaps[j] >= rpeak; j++ {
+			rpeak = gaps[j]
+		}
+
+		depths[i] = (lpeak - gaps[i]) + (rpeak - gaps[i])
+		if depths[i] > 0 {
+			minima = append(minima, i)
+		}
+	}
+	if len(minima) == 0 {
+		return nil
+	}
+
+	// Keeps the deepest minima, using Hearst's liberal cutoff.
+	var mean, variance float64
+	for _, i := range minima {
+		mean += depths[i]
+	}
+	mean /= float64(len(minima))
+	for _, i := range minima {
+		variance += (depths[i] - mean) * (depths[i] - mean)
+	}
+	cutoff := mean - math.Sqrt(variance/float64(len(minima)))/2
+
+	bounds := make([]Boundary, 0, len(minima))
+	for _, i := range minima {
+		if depths[i] >= cutoff {
+			bounds = append(bounds, Boundary{Sentence: i, Depth: depths[i]})
+		}
+	}
+	return bounds
+}
+
+// add adds the counts of src to dst.
+func add(dst, src map[string]float64) {
+	for word, count := range src {
+		dst[word] += count
+	}
+}
+
+// cosine returns the cosine similarity of the two vectors of counts.
+func cosine(a, b map[string]float64) float64 {
+	var dot, na, nb float64
+	for word, count := range a {
+		dot += count * b[word]
+		na += count * count
+	}
+	for _, count := range b {
+		nb += count * count
+	}
+
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(na*nb)
+}
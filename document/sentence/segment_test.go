@@ -0,0 +1,37 @@
+package sentence
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/algao1/basically"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTextTiling(t *testing.T) {
+	texts := []string{
+		"vaccine trial dose patients",
+		"vaccine dose immunity patients",
+		"trial immunity vaccine results",
+		"patients dose trial vaccine",
+		"election vote ballot candidate",
+		"candidate campaign vote polls",
+		"ballot polls election turnout",
+		"campaign candidate election vote",
+	}
+
+	sents := make([]*basically.Sentence, 0, len(texts))
+	for _, text := range texts {
+		sent := &basically.Sentence{Raw: text}
+		for _, word := range strings.Fields(text) {
+			sent.Tokens = append(sent.Tokens, &basically.Token{Tag: "NN", Text: word})
+		}
+		sents = append(sents, sent)
+	}
+
+	bounds := TextTiling(sents, nil, 3)
+	if assert.Len(t, bounds, 1) {
+		assert.Equal(t, 4, bounds[0].Sentence)
+		assert.Greater(t, bounds[0].Depth, 0.0)
+	}
+}
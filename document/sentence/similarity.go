@@ -16,7 +16,7 @@ func DefaultSimilarity(n1, n2 []*basically.Token, filter basically.TokenFilter)
 	freqTable := make(map[string]int)
 
 	for _, tok := range append(n1, n2...) {
-		norm := porter2.Stem(strings.ToLower(tok.Text))
+		norm := normalize(tok)
 		if _, ok := freqTable[norm]; ok && filter(tok) {
 			ret++
 		} else if !ok {
@@ -24,5 +24,15 @@ func DefaultSimilarity(n1, n2 []*basically.Token, filter basically.TokenFilter)
 		}
 	}
 
-	return ret / (math.Log10(l1) + math.Log10(l2))
+	// Avoid dividing by zero when comparing single-token sentences (such as headings).
+	norm := math.Log10(l1) + math.Log10(l2)
+	if norm <= 0 {
+		return 0
+	}
+	return ret / norm
+}
+
+// normalize converts the token to lowercase, and stems it.
+func normalize(tok *basically.Token) string {
+	return porter2.Stem(strings.ToLower(tok.Text))
 }
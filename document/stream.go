@@ -0,0 +1,106 @@
+package document
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/algao1/basically"
+	"github.com/algao1/basically/document/sentence"
+)
+
+// The number of ranking iterations after adding text. Ranking is warm-started from the
+// previous scores, so fewer iterations are needed than when summarizing from scratch.
+const streamIters = 3
+
+// A Stream summarizes growing text, such as a chat channel or a log, as it arrives. Only new
+// text is parsed, and only the edges of new sentences are constructed. If a window is set,
+// only the latest sentences are kept, and older ones are evicted. Streams have no focus.
+type Stream struct {
+	// Configurations and dependency injection.
+	Configs    *Configs
+	Summarizer basically.IncrementalSummarizer
+	Parser     basically.Parser
+	// Stream related information.
+	Sentences []*basically.Sentence // The sentences within the window, in order.
+	Window    int                   // The maximum number of sentences kept, or 0 to keep all.
+	count     int                   // The number of sentences added so far.
+	tokens    int                   // The number of tokens added so far.
+	ranked    bool                  // Whether the sentences were ranked since the last addition.
+}
+
+// CreateStream creates an empty stream, keeping at most window sentences (or all, if 0).
+func CreateStream(s basically.IncrementalSummarizer, p basically.Parser, window int,
+	cfgs ...Config) (*Stream, error) {
+	configs, err := configure(cfgs)
+	if err != nil {
+		return nil, err
+	}
+
+	st := &Stream{
+		Configs:    configs,
+		Summarizer: s,
+		Parser:     p,
+		Sentences:  make([]*basically.Sentence, 0),
+		Window:     window,
+	}
+	s.Initialize(nil, configs.similarity, configs.sfilter, nil, configs.threshold)
+
+	return st, nil
+}
+
+// Add parses the text, and adds its sentences to the stream, evicting the oldest sentences
+// if the window is full.
+func (st *Stream) Add(text string) error {
+	sents, _, err := st.Parser.ParseDocument(text, st.Configs.quotations)
+	if err != nil {
+		return fmt.Errorf("%q: %w", "unable to parse text", err)
+	}
+
+	// Renumbers the sentences and tokens to follow those previously added.
+	for _, sent := range sents {
+		sent.Order += st.count
+		for _, tok := range sent.Tokens {
+			tok.Order += st.tokens
+			tok.Sentence = sent.Order
+		}
+	}
+	for _, sent := range sents {
+		st.tokens += len(sent.Tokens)
+	}
+	st.count += len(sents)
+
+	st.Summarizer.Insert(sents, st.Configs.similarity, st.Configs.sfilter, nil, st.Configs.threshold)
+	st.Sentences = append(st.Sentences, sents...)
+	if st.Window > 0 && len(st.Sentences) > st.Window {
+		evicted := len(st.Sentences) - st.Window
+		st.Summarizer.Evict(evicted)
+		st.Sentences = append([]*basically.Sentence(nil), st.Sentences[evicted:]...)
+	}
+	st.ranked = false
+
+	return nil
+}
+
+// Summarize returns a summary of the current sentences of at most the given length, in order.
+func (st *Stream) Summarize(length int) []*basically.Sentence {
+	if !st.ranked {
+		st.Summarizer.Rank(streamIters)
+		st.ranked = true
+	}
+
+	sents := make([]*basically.Sentence, len(st.Sentences))
+	copy(sents, st.Sentences)
+	sort.SliceStable(sents, func(i, j int) bool { return sents[i].Score > sents[j].Score })
+	if length > len(sents) {
+		length = len(sents)
+	}
+
+	sums := ordered(sents[:length])
+	for idx := range sums {
+		sums[idx] = compress(st.Configs, sums[idx])
+		if !st.Configs.conjunctions {
+			sentence.RemoveConj(sums[idx])
+		}
+	}
+	return sums
+}
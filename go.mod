@@ -11,7 +11,7 @@ require (
 	github.com/stretchr/testify v1.6.1
 	github.com/surge/glog v0.0.0-20141108051140-2578deb2b95c // indirect
 	github.com/surgebase/porter2 v0.0.0-20150829210152-56e4718818e8
-	golang.org/x/text v0.3.5 // indirect
+	golang.org/x/text v0.3.5
 	gonum.org/v1/gonum v0.8.2
 	gopkg.in/neurosnap/sentences.v1 v1.0.6
 )
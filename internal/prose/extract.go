@@ -109,19 +109,28 @@ func newMaxentClassifier(
 // marshal saves the model to disk.
 func (m *binaryMaxentClassifier) marshal(path string) error {
 	folder := filepath.Join(path, "Maxent")
-	err := os.Mkdir(folder, os.ModePerm)
+	if err := os.MkdirAll(folder, os.ModePerm); err != nil {
+		return err
+	}
 	for i, entry := range []string{"labels", "mapping", "weights"} {
-		component, _ := os.Create(filepath.Join(folder, entry+".gob"))
+		component, err := os.Create(filepath.Join(folder, entry+".gob"))
+		if err != nil {
+			return err
+		}
 		encoder := gob.NewEncoder(component)
 		if i == 0 {
-			checkError(encoder.Encode(m.labels))
+			err = encoder.Encode(m.labels)
 		} else if i == 1 {
-			checkError(encoder.Encode(m.mapping))
+			err = encoder.Encode(m.mapping)
 		} else {
-			checkError(encoder.Encode(m.weights))
+			err = encoder.Encode(m.weights)
+		}
+		component.Close()
+		if err != nil {
+			return err
 		}
 	}
-	return err
+	return nil
 }
 
 // entityExtracter is a maximum entropy classifier.
@@ -162,13 +171,16 @@ func (e *entityExtracter) chunk(tokens []*Token) []Entity {
 	end := ""
 
 	parts := []*Token{}
-	idx := 0
+	idx, start := 0, 0
 
-	for _, tok := range tokens {
+	for i, tok := range tokens {
 		label := tok.Label
 		if (label != "O" && label != end) ||
 			(idx > 0 && tok.Tag == parts[idx-1].Tag) ||
 			(idx > 0 && tok.Tag == "CD" && parts[idx-1].Label != "O") {
+			if idx == 0 {
+				start = i
+			}
 			end = strings.Replace(label, "B", "I", 1)
 			parts = append(parts, tok)
 			idx++
@@ -177,7 +189,7 @@ func (e *entityExtracter) chunk(tokens []*Token) []Entity {
 			if label != "O" {
 				parts = append(parts, tok)
 			}
-			entities = append(entities, coalesce(parts))
+			entities = append(entities, coalesce(parts, start))
 
 			end = ""
 			parts = []*Token{}
@@ -185,6 +197,11 @@ func (e *entityExtracter) chunk(tokens []*Token) []Entity {
 		}
 	}
 
+	// The tokens may end in the middle of an entity.
+	if idx > 0 {
+		entities = append(entities, coalesce(parts, start))
+	}
+
 	return entities
 }
 
@@ -392,7 +409,7 @@ func parseEntities(ents []string) string {
 	return strings.Split(ents[0], "-")[1]
 }
 
-func coalesce(parts []*Token) Entity {
+func coalesce(parts []*Token, start int) Entity {
 	length := len(parts)
 	labels := make([]string, length)
 	tokens := make([]string, length)
@@ -403,6 +420,8 @@ func coalesce(parts []*Token) Entity {
 	return Entity{
 		Label: parseEntities(labels),
 		Text:  strings.Join(tokens, " "),
+		Start: start,
+		End:   start + length,
 	}
 }
 
@@ -1,8 +1,11 @@
 package prose
 
 import (
+	"encoding/gob"
 	"os"
 	"path/filepath"
+	"strings"
+	"unicode/utf8"
 )
 
 // A Model holds the structures and data used internally by prose.
@@ -54,60 +57,120 @@ func ModelFromData(name string, sources ...DataSource) *Model {
 
 // ModelFromDisk loads a Model from the user-provided location.
 func ModelFromDisk(path string) *Model {
-	name, classifier := loadClassifier(path)
+	model, err := LoadModel(path)
+	checkError(err)
+	return model
+}
+
+// LoadModel loads a Model from the user-provided location, returning an
+// error if the model is missing or malformed.
+func LoadModel(path string) (*Model, error) {
+	name, classifier, err := loadClassifier(path)
+	if err != nil {
+		return nil, err
+	}
 	return &Model{
 		Name: name,
 
 		extracter: classifier,
-		Tagger:    newPerceptronTagger()}
+		Tagger:    newPerceptronTagger()}, nil
 }
 
 // Write saves a Model to the user-provided location.
 func (m *Model) Write(path string) error {
-	err := os.MkdirAll(path, os.ModePerm)
-	// m.Tagger.model.Marshal(path)
-	checkError(m.extracter.model.marshal(path))
-	return err
+	if err := os.MkdirAll(path, os.ModePerm); err != nil {
+		return err
+	}
+	return m.extracter.model.marshal(path)
+}
+
+// Extract assigns IOB labels to the given (tagged) tokens, and returns the
+// named-entities found within them.
+func (m *Model) Extract(tokens []*Token) []Entity {
+	tokens = m.extracter.classify(tokens)
+	return m.extracter.chunk(tokens)
+}
+
+// Locate returns the named-entities found in `text`, as spans relative to
+// `text` (measured in runes).
+func (m *Model) Locate(text string) []LabeledEntity {
+	tokens := m.Tagger.Tag(NewIterTokenizer().Tokenize(text))
+	ents := m.Extract(tokens)
+
+	// Find the offsets of each token within the text.
+	starts, ends := make([]int, len(tokens)), make([]int, len(tokens))
+	cursor := 0
+	for i, tok := range tokens {
+		idx := strings.Index(text[cursor:], tok.Text)
+		if idx < 0 {
+			starts[i], ends[i] = cursor, cursor
+			continue
+		}
+		starts[i] = cursor + idx
+		ends[i] = starts[i] + len(tok.Text)
+		cursor = ends[i]
+	}
+
+	spans := make([]LabeledEntity, 0, len(ents))
+	for _, ent := range ents {
+		spans = append(spans, LabeledEntity{
+			Start: utf8.RuneCountInString(text[:starts[ent.Start]]),
+			End:   utf8.RuneCountInString(text[:ends[ent.End-1]]),
+			Label: ent.Label,
+		})
+	}
+	return spans
 }
 
-/* TODO: External taggers
-func loadTagger(path string) *perceptronTagger {
+// TaggerFromDisk loads a PerceptronTagger from the user-provided location.
+func TaggerFromDisk(path string) (*PerceptronTagger, error) {
 	var wts map[string]map[string]float64
 	var tags map[string]string
 	var classes []string
 
 	loc := filepath.Join(path, "AveragedPerceptron")
-	dec := getDiskAsset(filepath.Join(loc, "weights.gob"))
-	checkError(dec.Decode(&wts))
-
-	dec = getDiskAsset(filepath.Join(loc, "tags.gob"))
-	checkError(dec.Decode(&tags))
-
-	dec = getDiskAsset(filepath.Join(loc, "classes.gob"))
-	checkError(dec.Decode(&classes))
+	for name, target := range map[string]interface{}{
+		"weights.gob": &wts, "tags.gob": &tags, "classes.gob": &classes} {
+		if err := decodeFile(filepath.Join(loc, name), target); err != nil {
+			return nil, err
+		}
+	}
 
 	model := newAveragedPerceptron(wts, tags, classes)
-	return newTrainedPerceptronTagger(model)
-}*/
+	return newTrainedPerceptronTagger(model), nil
+}
+
+// Write saves a PerceptronTagger to the user-provided location.
+func (pt *PerceptronTagger) Write(path string) error {
+	return pt.model.marshal(path)
+}
 
-func loadClassifier(path string) (string, *entityExtracter) {
+func loadClassifier(path string) (string, *entityExtracter, error) {
 	var mapping map[string]int
 	var weights []float64
 	var labels []string
 
 	loc := filepath.Join(path, "Maxent")
-	dec := getDiskAsset(filepath.Join(loc, "mapping.gob"))
-	checkError(dec.Decode(&mapping))
-
-	dec = getDiskAsset(filepath.Join(loc, "weights.gob"))
-	checkError(dec.Decode(&weights))
-
-	dec = getDiskAsset(filepath.Join(loc, "labels.gob"))
-	checkError(dec.Decode(&labels))
+	for name, target := range map[string]interface{}{
+		"mapping.gob": &mapping, "weights.gob": &weights, "labels.gob": &labels} {
+		if err := decodeFile(filepath.Join(loc, name), target); err != nil {
+			return "", nil, err
+		}
+	}
 
 	model := newMaxentClassifier(weights, mapping, labels)
 	name := filepath.Base(path)
-	return name, newTrainedEntityExtracter(model)
+	return name, newTrainedEntityExtracter(model), nil
+}
+
+// decodeFile decodes the gob-encoded file at `path` into `target`.
+func decodeFile(path string, target interface{}) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return gob.NewDecoder(f).Decode(target)
 }
 
 // DefaultModel ...
@@ -0,0 +1,317 @@
+package prose
+
+import (
+	"math"
+	"regexp"
+	"strings"
+
+	"gopkg.in/neurosnap/sentences.v1"
+	"gopkg.in/neurosnap/sentences.v1/data"
+)
+
+// Cut-off values for the log-likelihood scores used during training.
+//
+// See https://www.nltk.org/_modules/nltk/tokenize/punkt.html for more
+// information.
+const (
+	punktAbbrev      = 0.3
+	punktCollocation = 7.88
+	punktSentStarter = 30.0
+	punktMinColloc   = 1
+)
+
+// Orthographic context flags, matching those used by `sentences.OrthoContext`.
+const (
+	orthoBegUc = 1 << 1
+	orthoMidUc = 1 << 2
+	orthoUnkUc = 1 << 3
+	orthoBegLc = 1 << 4
+	orthoMidLc = 1 << 5
+	orthoUnkLc = 1 << 6
+)
+
+var reNonPunct = regexp.MustCompile(`[^\W\d]`)
+
+// PunktTrainer learns the parameters of a Punkt sentence tokenizer
+// (abbreviations, collocations, sentence starters and orthographic context)
+// from unannotated text.
+//
+// See Kiss & Strunk (2006), "Unsupervised Multilingual Sentence Boundary
+// Detection", for details.
+type PunktTrainer struct {
+	storage *sentences.Storage
+	word    *wordTokenizer
+
+	tokens     []*sentences.Token
+	typeFreq   map[string]int
+	periodToks int
+}
+
+// NewPunktTrainer creates a new PunktTrainer. Learned parameters are added on
+// top of `base`, which may be nil to train from scratch.
+func NewPunktTrainer(base *sentences.Storage) *PunktTrainer {
+	storage := sentences.NewStorage()
+	if base != nil {
+		mergePunkt(storage, base)
+	}
+
+	return &PunktTrainer{
+		storage:  storage,
+		word:     newWordTokenizer(sentences.NewPunctStrings()),
+		typeFreq: make(map[string]int),
+	}
+}
+
+// DefaultPunktStorage loads the parameters of the bundled English model.
+func DefaultPunktStorage() (*sentences.Storage, error) {
+	b, err := data.Asset("data/english.json")
+	if err != nil {
+		return nil, err
+	}
+	return sentences.LoadTraining(b)
+}
+
+// Train collects statistics from `text`. It may be called multiple times
+// before calling Finalize.
+func (pt *PunktTrainer) Train(text string) {
+	for _, tok := range pt.word.Tokenize(text, false) {
+		typ := pt.word.Type(tok)
+		pt.typeFreq[typ]++
+		if pt.word.HasPeriodFinal(tok) {
+			pt.periodToks++
+		}
+		pt.tokens = append(pt.tokens, tok)
+	}
+}
+
+// Finalize computes the Punkt parameters from the collected statistics, and
+// returns them merged with the base parameters.
+func (pt *PunktTrainer) Finalize() *sentences.Storage {
+	total := 0
+	for _, count := range pt.typeFreq {
+		total += count
+	}
+	if total == 0 {
+		return pt.storage
+	}
+	n := float64(total)
+
+	pt.findAbbrevTypes(n)
+
+	// First pass annotation, based purely on the (updated) abbreviation types.
+	annotation := sentences.NewTypeBasedAnnotation(pt.storage, pt.word.PunctStrings, pt.word)
+	annotation.Annotate(pt.tokens)
+
+	pt.findOrthoContext()
+
+	sentBreaks := 0
+	starterFreq := make(map[string]int)
+	collocFreq := make(map[string]int)
+	for i, tok := range pt.tokens {
+		if !tok.SentBreak {
+			continue
+		}
+		sentBreaks++
+		if i+1 < len(pt.tokens) {
+			starterFreq[pt.word.TypeNoSentPeriod(pt.tokens[i+1])]++
+		}
+	}
+	for i := 0; i+1 < len(pt.tokens); i++ {
+		tok1, tok2 := pt.tokens[i], pt.tokens[i+1]
+		if pt.isPotentialCollocation(tok1, tok2) {
+			key := pt.word.TypeNoPeriod(tok1) + "," + pt.word.TypeNoSentPeriod(tok2)
+			collocFreq[key]++
+		}
+	}
+
+	if sentBreaks > 0 {
+		pt.findSentStarters(starterFreq, float64(sentBreaks), n)
+	}
+	pt.findCollocations(collocFreq, n)
+
+	return pt.storage
+}
+
+// findAbbrevTypes marks period-final types as abbreviations when their
+// log-likelihood score exceeds the cut-off.
+func (pt *PunktTrainer) findAbbrevTypes(n float64) {
+	for typ := range pt.typeFreq {
+		if !strings.HasSuffix(typ, ".") || len(typ) < 2 {
+			continue
+		}
+		typ = typ[:len(typ)-1]
+		if typ == "##number##" || !reNonPunct.MatchString(typ) || pt.storage.AbbrevTypes.Has(typ) {
+			continue
+		}
+
+		periods := float64(strings.Count(typ, ".") + 1)
+		nonPeriods := float64(len(typ)) - periods + 1
+		withPeriod := float64(pt.typeFreq[typ+"."])
+		withoutPeriod := float64(pt.typeFreq[typ])
+
+		ll := dunningLogLikelihood(withPeriod+withoutPeriod, float64(pt.periodToks), withPeriod, n)
+		fLength := math.Exp(-nonPeriods)
+		fPenalty := math.Pow(nonPeriods, -withoutPeriod)
+
+		if ll*fLength*periods*fPenalty >= punktAbbrev {
+			pt.storage.AbbrevTypes.Add(typ)
+		}
+	}
+}
+
+// findOrthoContext records the orthographic contexts in which each type occurs.
+func (pt *PunktTrainer) findOrthoContext() {
+	context := "internal"
+	for _, tok := range pt.tokens {
+		if tok.ParaStart && context != "unknown" {
+			context = "initial"
+		}
+		if tok.LineStart && context == "internal" {
+			context = "unknown"
+		}
+
+		typ := pt.word.TypeNoSentPeriod(tok)
+		if flag := orthoFlag(context, pt.word.FirstUpper(tok), pt.word.FirstLower(tok)); flag != 0 {
+			pt.storage.OrthoContext[typ] |= flag
+		}
+
+		if tok.SentBreak {
+			if pt.isNumber(tok) || pt.word.IsInitial(tok) {
+				context = "unknown"
+			} else {
+				context = "initial"
+			}
+		} else if tok.Abbr || pt.word.IsEllipsis(tok) {
+			context = "unknown"
+		} else {
+			context = "internal"
+		}
+	}
+}
+
+// findSentStarters marks types that frequently follow sentence breaks as
+// sentence starters.
+func (pt *PunktTrainer) findSentStarters(starterFreq map[string]int, sentBreaks, n float64) {
+	for typ, count := range starterFreq {
+		if typ == "" {
+			continue
+		}
+
+		atBreak := float64(count)
+		typCount := float64(pt.typeFreq[typ] + pt.typeFreq[typ+"."])
+		if typCount < atBreak {
+			continue
+		}
+
+		ll := colLogLikelihood(sentBreaks, typCount, atBreak, n)
+		if ll >= punktSentStarter && n/sentBreaks > typCount/atBreak {
+			pt.storage.SentStarters.Add(typ)
+		}
+	}
+}
+
+// findCollocations marks pairs of types that frequently occur around a
+// non-breaking period as collocations.
+func (pt *PunktTrainer) findCollocations(collocFreq map[string]int, n float64) {
+	for key, count := range collocFreq {
+		types := strings.SplitN(key, ",", 2)
+		typ1, typ2 := types[0], types[1]
+		if pt.storage.SentStarters.Has(typ1) || pt.storage.SentStarters.Has(typ2) {
+			continue
+		}
+
+		colCount := float64(count)
+		typ1Count := float64(pt.typeFreq[typ1] + pt.typeFreq[typ1+"."])
+		typ2Count := float64(pt.typeFreq[typ2] + pt.typeFreq[typ2+"."])
+		if typ1Count <= 1 || typ2Count <= 1 || colCount <= punktMinColloc ||
+			colCount > math.Min(typ1Count, typ2Count) {
+			continue
+		}
+
+		ll := colLogLikelihood(typ1Count, typ2Count, colCount, n)
+		if ll >= punktCollocation && n/typ1Count > typ2Count/colCount {
+			pt.storage.Collocations.Add(key)
+		}
+	}
+}
+
+func (pt *PunktTrainer) isPotentialCollocation(tok1, tok2 *sentences.Token) bool {
+	candidate := tok1.Abbr || (tok1.SentBreak && (pt.isNumber(tok1) || pt.word.IsInitial(tok1)))
+	return candidate &&
+		reNonPunct.MatchString(pt.word.Type(tok1)) &&
+		reNonPunct.MatchString(pt.word.Type(tok2))
+}
+
+func (pt *PunktTrainer) isNumber(tok *sentences.Token) bool {
+	return strings.HasPrefix(pt.word.Type(tok), "##number##")
+}
+
+func orthoFlag(context string, upper, lower bool) int {
+	switch {
+	case context == "initial" && upper:
+		return orthoBegUc
+	case context == "internal" && upper:
+		return orthoMidUc
+	case context == "unknown" && upper:
+		return orthoUnkUc
+	case context == "initial" && lower:
+		return orthoBegLc
+	case context == "internal" && lower:
+		return orthoMidLc
+	case context == "unknown" && lower:
+		return orthoUnkLc
+	}
+	return 0
+}
+
+// mergePunkt adds the parameters in `src` to `dst`.
+func mergePunkt(dst, src *sentences.Storage) {
+	for k, v := range src.AbbrevTypes {
+		dst.AbbrevTypes[k] = v
+	}
+	for k, v := range src.Collocations {
+		dst.Collocations[k] = v
+	}
+	for k, v := range src.SentStarters {
+		dst.SentStarters[k] = v
+	}
+	for k, v := range src.OrthoContext {
+		dst.OrthoContext[k] |= v
+	}
+}
+
+// binomialLog returns the log-likelihood of `k` successes in `n` trials with
+// success probability `p`.
+func binomialLog(k, n, p float64) float64 {
+	p = math.Min(math.Max(p, 1e-12), 1-1e-12)
+	return k*math.Log(p) + (n-k)*math.Log(1-p)
+}
+
+// dunningLogLikelihood computes the log-likelihood estimate described in
+// Kiss & Strunk (2006), used for abbreviation detection.
+func dunningLogLikelihood(countA, countB, countAB, n float64) float64 {
+	null := binomialLog(countAB, countA, countB/n)
+	alt := binomialLog(countAB, countA, 0.99)
+	return -2.0 * (null - alt)
+}
+
+// colLogLikelihood computes Dunning's log-likelihood ratio for the
+// co-occurrence of `a` and `b`.
+func colLogLikelihood(countA, countB, countAB, n float64) float64 {
+	p := countB / n
+	p1 := countAB / countA
+	p2 := (countB - countAB) / (n - countA)
+
+	summand1 := binomialLog(countAB, countA, p)
+	summand2 := binomialLog(countB-countAB, n-countA, p)
+
+	var summand3, summand4 float64
+	if countA != countAB {
+		summand3 = binomialLog(countAB, countA, p1)
+	}
+	if countB != countAB {
+		summand4 = binomialLog(countB-countAB, n-countA, p2)
+	}
+
+	return -2.0 * (summand1 + summand2 - summand3 - summand4)
+}
@@ -25,7 +25,6 @@ import (
 	"strings"
 
 	"gopkg.in/neurosnap/sentences.v1"
-	"gopkg.in/neurosnap/sentences.v1/data"
 )
 
 // PunktSentenceTokenizer is an extension of the Go implementation of the Punkt
@@ -47,6 +46,18 @@ func NewPunktSentenceTokenizer() *PunktSentenceTokenizer {
 	return &pt
 }
 
+// NewCustomPunktSentenceTokenizer creates a new PunktSentenceTokenizer using
+// the given Punkt parameters (see PunktTrainer).
+func NewCustomPunktSentenceTokenizer(s *sentences.Storage) *PunktSentenceTokenizer {
+	var pt PunktSentenceTokenizer
+	var err error
+
+	pt.tokenizer, err = newSentenceTokenizer(s)
+	checkError(err)
+
+	return &pt
+}
+
 // Segment splits text into sentences.
 func (p PunktSentenceTokenizer) Segment(text string) []Sentence {
 	tokens := p.tokenizer.Tokenize(text)
@@ -70,12 +81,8 @@ func newSentenceTokenizer(s *sentences.Storage) (*sentences.DefaultSentenceToken
 	training := s
 
 	if training == nil {
-		b, err := data.Asset("data/english.json")
-		if err != nil {
-			return nil, err
-		}
-
-		training, err = sentences.LoadTraining(b)
+		var err error
+		training, err = DefaultPunktStorage()
 		if err != nil {
 			return nil, err
 		}
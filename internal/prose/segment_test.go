@@ -129,6 +129,49 @@ func TestEnglishSupervisedAbbrev(t *testing.T) {
 	}
 }
 
+func TestPunktTrainer(t *testing.T) {
+	corpus := []string{
+		"The results are shown in Fig. 2 and discussed below.",
+		"Patients received approx. 20 mg of the drug each day.",
+		"See Fig. 4 for the full dose response curve.",
+		"The trial enrolled approx. 300 patients across sites.",
+		"Fig. 1 summarizes the study design. It was approved in May.",
+		"Each cohort lasted approx. six weeks in total.",
+	}
+
+	base, err := DefaultPunktStorage()
+	checkError(err)
+
+	trainer := NewPunktTrainer(base)
+	for i := 0; i < 25; i++ {
+		for _, text := range corpus {
+			trainer.Train(text + "\n\n")
+		}
+	}
+	storage := trainer.Finalize()
+
+	if !storage.AbbrevTypes.Has("fig") || !storage.AbbrevTypes.Has("approx") {
+		t.Fatalf("Expected abbreviations to be learned: %v", storage.AbbrevTypes.Array())
+	}
+
+	segmenter := NewCustomPunktSentenceTokenizer(storage)
+	actual := segmenter.Segment("Dosing was approx. 20 mg. See Fig. 3 for the curve.")
+	expected := []string{
+		"Dosing was approx. 20 mg.",
+		"See Fig. 3 for the curve.",
+	}
+
+	if len(actual) != len(expected) {
+		t.Fatalf("Actual: %d, Expected: %d", len(actual), len(expected))
+	}
+
+	for index, sent := range actual {
+		if sent.Text != expected[index] {
+			t.Fatalf("Actual: %s\nExpected: %s", sent, expected[index])
+		}
+	}
+}
+
 func TestEnglishSemicolon(t *testing.T) {
 	actualText := "I am here; you are over there.  Will the tokenizer output two complete sentences?"
 	doc, _ := makeSegmenter(actualText)
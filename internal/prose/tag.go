@@ -21,7 +21,11 @@
 package prose
 
 import (
+	"encoding/gob"
 	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
@@ -39,25 +43,80 @@ func (t TupleSlice) Len() int { return len(t) }
 func (t TupleSlice) Swap(i, j int) { t[i], t[j] = t[j], t[i] }
 
 // ReadTagged converts pre-tagged input into a TupleSlice suitable for training.
+// Each line holds one sentence of space-separated tokens, with every token's
+// tag following the last occurrence of `sep`. Blank lines and tokens without
+// a tag are ignored.
 func ReadTagged(text, sep string) TupleSlice {
 	lines := strings.Split(text, "\n")
-	length := len(lines)
-	t := make(TupleSlice, length)
-	for i, sent := range lines {
-		set := strings.Split(sent, " ")
-		length = len(set)
-		tokens := make([]string, length)
-		tags := make([]string, length)
-		for j, token := range set {
-			parts := strings.Split(token, sep)
-			tokens[j] = parts[0]
-			tags[j] = parts[1]
+	t := make(TupleSlice, 0, len(lines))
+	for _, sent := range lines {
+		set := strings.Fields(sent)
+		tokens := make([]string, 0, len(set))
+		tags := make([]string, 0, len(set))
+		for _, token := range set {
+			idx := strings.LastIndex(token, sep)
+			if idx <= 0 || idx+len(sep) == len(token) {
+				continue
+			}
+			tokens = append(tokens, token[:idx])
+			tags = append(tags, token[idx+len(sep):])
+		}
+		if len(tokens) > 0 {
+			t = append(t, [][]string{tokens, tags})
 		}
-		t[i] = [][]string{tokens, tags}
 	}
 	return t
 }
 
+// ReadCoNLL converts CoNLL-formatted input into a TupleSlice suitable for
+// training. Sentences are separated by blank lines. Both CoNLL-U (tab-separated,
+// using the XPOS column if present and UPOS otherwise) and the CoNLL-2000/2003
+// formats (a word followed by its tag) are supported.
+func ReadCoNLL(text string) TupleSlice {
+	t := TupleSlice{}
+	tokens, tags := []string{}, []string{}
+
+	flush := func() {
+		if len(tokens) > 0 {
+			t = append(t, [][]string{tokens, tags})
+		}
+		tokens, tags = []string{}, []string{}
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(line) == "" {
+			flush()
+			continue
+		} else if strings.HasPrefix(line, "#") || strings.HasPrefix(line, "-DOCSTART-") {
+			continue
+		}
+
+		var word, tag string
+		if cols := strings.Split(line, "\t"); len(cols) >= 10 {
+			// Skip multi-word tokens (e.g. "1-2") and empty nodes (e.g. "1.1").
+			if strings.ContainsAny(cols[0], "-.") {
+				continue
+			}
+			word, tag = cols[1], cols[4]
+			if tag == "_" {
+				tag = cols[3]
+			}
+		} else if cols := strings.Fields(line); len(cols) >= 2 {
+			word, tag = cols[0], cols[1]
+		}
+
+		if word == "" || tag == "" || tag == "_" {
+			continue
+		}
+		tokens = append(tokens, word)
+		tags = append(tags, tag)
+	}
+	flush()
+
+	return t
+}
+
 var none = regexp.MustCompile(`^(?:0|\*[\w?]\*|\*\-\d{1,3}|\*[A-Z]+\*\-\d{1,3}|\*)$`)
 var keep = regexp.MustCompile(`^\-[A-Z]{3}\-$`)
 
@@ -69,9 +128,7 @@ type averagedPerceptron struct {
 	tagMap  map[string]string
 	weights map[string]map[string]float64
 
-	// TODO: Training
-	//
-	// instances float64
+	instances float64
 }
 
 // newAveragedPerceptron creates a new AveragedPerceptron model.
@@ -82,26 +139,41 @@ func newAveragedPerceptron(weights map[string]map[string]float64,
 		classes: classes, tagMap: tags, weights: weights}
 }
 
-/* TODO: Training API
-
-"github.com/shogo82148/go-shuffle"
-
 // marshal saves the model to disk.
 func (m *averagedPerceptron) marshal(path string) error {
 	folder := filepath.Join(path, "AveragedPerceptron")
-	err := os.Mkdir(folder, os.ModePerm)
+	if err := os.MkdirAll(folder, os.ModePerm); err != nil {
+		return err
+	}
 	for i, entry := range []string{"weights", "tags", "classes"} {
-		component, _ := os.Create(filepath.Join(folder, entry+".gob"))
+		component, err := os.Create(filepath.Join(folder, entry+".gob"))
+		if err != nil {
+			return err
+		}
 		encoder := gob.NewEncoder(component)
 		if i == 0 {
-			checkError(encoder.Encode(m.weights))
+			err = encoder.Encode(m.weights)
 		} else if i == 1 {
-			checkError(encoder.Encode(m.tagMap))
+			err = encoder.Encode(m.tagMap)
 		} else {
-			checkError(encoder.Encode(m.classes))
+			err = encoder.Encode(m.classes)
+		}
+		component.Close()
+		if err != nil {
+			return err
 		}
 	}
-	return err
+	return nil
+}
+
+// TrainPerceptronTagger trains a new PerceptronTagger on the given sentences
+// for the specified number of iterations.
+func TrainPerceptronTagger(sentences TupleSlice, iterations int) *PerceptronTagger {
+	model := newAveragedPerceptron(
+		make(map[string]map[string]float64), make(map[string]string), []string{})
+	pt := newTrainedPerceptronTagger(model)
+	pt.train(sentences, iterations)
+	return pt
 }
 
 // train an Averaged Perceptron model based on sentences.
@@ -109,6 +181,9 @@ func (pt *PerceptronTagger) train(sentences TupleSlice, iterations int) {
 	var guess string
 	var found bool
 
+	// A fixed seed keeps training reproducible.
+	rng := rand.New(rand.NewSource(1))
+
 	pt.makeTagMap(sentences)
 	for i := 0; i < iterations; i++ {
 		for _, tuple := range sentences {
@@ -116,14 +191,11 @@ func (pt *PerceptronTagger) train(sentences TupleSlice, iterations int) {
 			p1, p2 := "-START-", "-START2-"
 			context := []string{p1, p2}
 			for _, w := range words {
-				if w == "" {
-					continue
-				}
 				context = append(context, normalize(w))
 			}
 			context = append(context, []string{"-END-", "-END2-"}...)
 			for i, word := range words {
-				if guess, found = pt.tagMap[word]; !found {
+				if guess, found = pt.model.tagMap[word]; !found {
 					feats := featurize(i, context, word, p1, p2)
 					guess = pt.model.predict(feats)
 					pt.model.update(tags[i], guess, feats)
@@ -132,7 +204,7 @@ func (pt *PerceptronTagger) train(sentences TupleSlice, iterations int) {
 				p1 = guess
 			}
 		}
-		shuffle.Shuffle(sentences)
+		rng.Shuffle(sentences.Len(), sentences.Swap)
 	}
 	pt.model.averageWeights()
 }
@@ -144,7 +216,7 @@ func (m *averagedPerceptron) averageWeights() {
 			key := feat + "-" + class
 			total := m.totals[key]
 			total += (m.instances - m.stamps[key]) * weight
-			averaged, _ := stats.Round(total/m.instances, 3)
+			averaged := math.Round(total/m.instances*1000) / 1000
 			if averaged != 0.0 {
 				newWeights[class] = averaged
 			}
@@ -176,7 +248,7 @@ func (pt *PerceptronTagger) makeTagMap(sentences TupleSlice) {
 		tag, mode := maxValue(tagFreqs)
 		n := float64(sumValues(tagFreqs))
 		if n >= 20 && (float64(mode)/n) >= 0.97 {
-			pt.tagMap[word] = tag
+			pt.model.tagMap[word] = tag
 		}
 	}
 }
@@ -193,7 +265,7 @@ func maxValue(m map[string]int) (string, int) {
 	maxValue := 0
 	key := ""
 	for k, v := range m {
-		if v >= maxValue {
+		if v > maxValue || (v == maxValue && k < key) {
 			maxValue = v
 			key = k
 		}
@@ -227,7 +299,7 @@ func (m *averagedPerceptron) update(truth, guess string, feats map[string]float6
 
 func (m *averagedPerceptron) updateFeat(c, f string, v, w float64) {
 	key := f + "-" + c
-	m.totals[key] = (m.instances - m.stamps[key]) * w
+	m.totals[key] += (m.instances - m.stamps[key]) * v
 	m.stamps[key] = m.instances
 	m.weights[f][c] = w + v
 }
@@ -236,7 +308,7 @@ func (m *averagedPerceptron) addClass(class string) {
 	if !stringInSlice(class, m.classes) {
 		m.classes = append(m.classes, class)
 	}
-}*/
+}
 
 // PerceptronTagger is a port of Textblob's "fast and accurate" POS tagger.
 // See https://github.com/sloria/textblob-aptagger for details.
@@ -316,6 +388,13 @@ func (m *averagedPerceptron) predict(features map[string]float64) string {
 			scores[label] += value * weight
 		}
 	}
+
+	// Fall back to the known classes if none of the features are weighted.
+	if len(scores) == 0 {
+		for _, class := range m.classes {
+			scores[class] = 0
+		}
+	}
 	return max(scores)
 }
 
@@ -323,7 +402,7 @@ func max(scores map[string]float64) string {
 	var class string
 	max := math.Inf(-1)
 	for label, value := range scores {
-		if value > max {
+		if value > max || (value == max && label > class) {
 			max = value
 			class = label
 		}
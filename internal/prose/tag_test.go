@@ -3,6 +3,7 @@ package prose
 import (
 	"encoding/json"
 	"fmt"
+	"os"
 	"path/filepath"
 	"testing"
 
@@ -66,8 +67,6 @@ func BenchmarkTag(b *testing.B) {
 	}
 }
 
-/* TODO: POS training API
-
 var wsj = "Pierre|NNP Vinken|NNP ,|, 61|CD years|NNS old|JJ ,|, will|MD " +
 	"join|VB the|DT board|NN as|IN a|DT nonexecutive|JJ director|NN " +
 	"Nov.|NNP 29|CD .|.\nMr.|NNP Vinken|NNP is|VBZ chairman|NN of|IN " +
@@ -84,25 +83,51 @@ var wsj = "Pierre|NNP Vinken|NNP ,|, 61|CD years|NNS old|JJ ,|, will|MD " +
 
 func TestTrain(t *testing.T) {
 	sentences := ReadTagged(wsj, "|")
-	iter := random(5, 20)
-	tagger.Train(sentences, iter)
+	tagger := TrainPerceptronTagger(sentences, 10)
 
-	tagSet := []string{}
 	nrWords := 0
 	for _, tuple := range sentences {
 		nrWords += len(tuple[0])
-		for _, tag := range tuple[1] {
-			if !util.StringInSlice(tag, tagSet) {
-				tagSet = append(tagSet, tag)
-			}
-		}
+	}
+	assert.Equal(t, nrWords*10, int(tagger.model.instances))
+
+	temp := filepath.Join(testdata, "temp-tagger")
+	_ = os.RemoveAll(temp)
+	defer os.RemoveAll(temp)
+
+	checkError(tagger.Write(temp))
+	tagger, err := TaggerFromDisk(temp)
+	checkError(err)
+
+	tokens := []*Token{}
+	for _, word := range sentences[0][0] {
+		tokens = append(tokens, &Token{Text: word})
 	}
 
-	assert.Equal(t, nrWords*iter, int(tagger.model.instances))
-	assert.Subset(t, tagger.Classes(), tagSet)
+	correct := 0.0
+	for i, tok := range tagger.Tag(tokens) {
+		if tok.Tag == sentences[0][1][i] {
+			correct++
+		}
+	}
+	assert.True(t, correct/float64(len(tokens)) >= 0.9)
 }
 
-func random(min, max int) int {
-	rand.Seed(time.Now().Unix())
-	return rand.Intn(max-min) + min
-}*/
+func TestReadCoNLL(t *testing.T) {
+	conllu := "# sent_id = 1\n" +
+		"1\tPierre\tPierre\tPROPN\tNNP\t_\t2\tnsubj\t_\t_\n" +
+		"2\tjoined\tjoin\tVERB\tVBD\t_\t0\troot\t_\t_\n" +
+		"\n" +
+		"1-2\tdon't\t_\t_\t_\t_\t_\t_\t_\t_\n" +
+		"1\tdo\tdo\tAUX\t_\t_\t0\troot\t_\t_\n" +
+		"2\tn't\tnot\tPART\t_\t_\t1\tadvmod\t_\t_\n"
+	assert.Equal(t, TupleSlice{
+		{{"Pierre", "joined"}, {"NNP", "VBD"}},
+		{{"do", "n't"}, {"AUX", "PART"}},
+	}, ReadCoNLL(conllu))
+
+	conll := "-DOCSTART- -X- O O\n\nEU NNP I-NP I-ORG\nrejects VBZ I-VP O\n\n"
+	assert.Equal(t, TupleSlice{
+		{{"EU", "rejects"}, {"NNP", "VBZ"}},
+	}, ReadCoNLL(conll))
+}
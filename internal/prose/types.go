@@ -12,6 +12,8 @@ type Token struct {
 type Entity struct {
 	Text  string // The entity's actual content.
 	Label string // The entity's label.
+	Start int    // The index of the entity's first token.
+	End   int    // The index following the entity's last token.
 }
 
 // A Sentence represents a segmented portion of text.
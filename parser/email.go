@@ -0,0 +1,351 @@
+package parser
+
+import (
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net/mail"
+	"regexp"
+	"strings"
+
+	"github.com/algao1/basically"
+	"golang.org/x/text/encoding/htmlindex"
+	"golang.org/x/text/encoding/unicode"
+)
+
+var (
+	reAttribution = regexp.MustCompile(`^\s*On\s.*\bwrote:\s*$`)
+	reAttrStart   = regexp.MustCompile(`^\s*On\s.*\d`)
+	reWrote       = regexp.MustCompile(`\bwrote:\s*$`)
+	reForwarded   = regexp.MustCompile(`(?i)^\s*(?:-{2,}\s*(?:original message|forwarded message)\s*-{2,}|_{10,})\s*$`)
+	reOutlook     = regexp.MustCompile(`(?i)^\s*(?:sent|date|to):\s`)
+	reMobile      = regexp.MustCompile(`(?i)^\s*sent from my\s`)
+	reGreeting    = regexp.MustCompile(`(?i)^\s*(?:hi|hello|hey|dear|good (?:morning|afternoon|evening))\b(?:\s+[\pL'-]+){0,3}\s*(?:[,!]?\s*$|,\s+)`)
+	reSignOff     = regexp.MustCompile(`(?i)^\s*(?:thanks|thank you|many thanks|best|best regards|kind regards|regards|warm regards|cheers|sincerely|all the best)\s*[,.!]?\s*$`)
+	reDisclaimer  = regexp.MustCompile(`(?i)^\s*(?:confidentiality notice|disclaimer|if you are not the intended recipient|this (?:e-?mail|message|communication)\b.*\b(?:confidential|privileged|intended (?:solely )?for))`)
+	reSubjectRe   = regexp.MustCompile(`(?i)^(?:\s*(?:re|fwd?|aw|sv)\s*(?:\[\d+\])?:\s*)+`)
+	reSubjectHdr  = regexp.MustCompile(`(?im)^subject:[ \t]*`)
+	reBlockquote  = regexp.MustCompile(`(?is)<blockquote\b.*?</blockquote\s*>`)
+)
+
+// The maximum number of lines (e.g. the sender's name and title) following a sign-off,
+// for the sign-off to be stripped.
+const signOffLines = 4
+
+// A region is a part of the document, given by its offsets.
+type region struct {
+	start, end int
+}
+
+// email splits RFC 5322 messages, or an mbox of messages, into units. Each message's text
+// parts are parsed as plain text (or HTML, if there is no plain text), with quoted replies,
+// forwarded history, signatures, sign-offs and disclaimers removed. Subjects are kept as
+// headings, unless repeated by a reply. The sender, date and order of each message are
+// recorded on its units.
+func email(doc string) []unit {
+	var units []unit
+	subject, para := "", 0
+	for idx, r := range mbox(doc) {
+		raw := doc[r.start:r.end]
+		msg, err := mail.ReadMessage(strings.NewReader(raw))
+		if err != nil {
+			// Not a message, so it is parsed as plain text.
+			units = append(units, rebase(structure(raw), r.start, true, para)...)
+			if len(units) > 0 {
+				para = units[len(units)-1].paragraph + 1
+			}
+			continue
+		}
+
+		body, _ := io.ReadAll(msg.Body)
+		start := len(raw) - len(body)
+		us := textPart(msg.Header, string(body), r.start+start)
+
+		// The subject is a heading, unless it repeats the previous subject (e.g. "Re: ...").
+		dec := &mime.WordDecoder{CharsetReader: charsetReader}
+		subj, err := dec.DecodeHeader(msg.Header.Get("Subject"))
+		if err != nil {
+			subj = msg.Header.Get("Subject")
+		}
+		subj = strings.TrimSpace(reSubjectRe.ReplaceAllString(subj, ""))
+		if subj != "" && !strings.EqualFold(subj, subject) {
+			b := &builder{}
+			if i := strings.Index(raw[:start], subj); i >= 0 {
+				b.write(subj, r.start+i)
+			} else if loc := reSubjectHdr.FindStringIndex(raw[:start]); loc != nil {
+				b.insert(subj, r.start+loc[1])
+			} else {
+				b.insert(subj, r.start)
+			}
+			if u, ok := b.unit(basically.Heading, 1, 0); ok {
+				us = append([]unit{u}, rebase(us, 0, true, 1)...)
+			}
+			subject = subj
+		}
+
+		sender := msg.Header.Get("From")
+		if addr, err := mail.ParseAddress(sender); err == nil {
+			sender = addr.Name
+			if sender == "" {
+				sender = addr.Address
+			}
+		}
+		date, _ := msg.Header.Date()
+
+		us = rebase(us, 0, true, para)
+		for i := range us {
+			us[i].speaker, us[i].date, us[i].message = sender, date, idx
+		}
+		units = append(units, us...)
+		if len(units) > 0 {
+			para = units[len(units)-1].paragraph + 1
+		}
+	}
+
+	return units
+}
+
+// mbox splits a mailbox into its messages, skipping the "From " lines separating them.
+// A document which is not a mailbox is a single message.
+func mbox(doc string) []region {
+	ls := lines(doc)
+	if !strings.HasPrefix(ls[0].text, "From ") {
+		return []region{{0, len(doc)}}
+	}
+
+	var regions []region
+	for i, l := range ls {
+		if !strings.HasPrefix(l.text, "From ") || (i > 0 && !ls[i-1].blank()) {
+			continue
+		}
+		if len(regions) > 0 {
+			regions[len(regions)-1].end = l.start
+		}
+		start := len(doc)
+		if i+1 < len(ls) {
+			start = ls[i+1].start
+		}
+		regions = append(regions, region{start, len(doc)})
+	}
+	return regions
+}
+
+// textPart splits the text of a message (or MIME part) into units, given its headers, its body,
+// and the offset of its body. Multipart bodies are split into their parts, preferring plain text
+// for alternative parts, and skipping attachments.
+func textPart(header mail.Header, body string, start int) []unit {
+	mediatype, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		mediatype = "text/plain"
+	}
+	if strings.HasPrefix(strings.ToLower(header.Get("Content-Disposition")), "attachment") {
+		return nil
+	}
+
+	switch {
+	case strings.HasPrefix(mediatype, "multipart/"):
+		var units, alt []unit
+		plain := false
+		for _, r := range multipart(body, params["boundary"]) {
+			raw := body[r.start:r.end]
+			part, err := mail.ReadMessage(strings.NewReader(raw))
+			if err != nil {
+				continue
+			}
+			pbody, _ := io.ReadAll(part.Body)
+			us := textPart(part.Header, string(pbody), start+len(body[:r.end])-len(pbody))
+
+			if mediatype != "multipart/alternative" {
+				units = append(units, us...)
+			} else if len(us) > 0 && !plain {
+				// Plain text is preferred over other alternatives.
+				alt = us
+				plain = strings.HasPrefix(strings.ToLower(part.Header.Get("Content-Type")), "text/plain")
+			}
+		}
+		if mediatype == "multipart/alternative" {
+			return alt
+		}
+		return units
+	case mediatype == "text/plain":
+		text, exact := decode(header, body)
+		return rebase(structure(clean(text)), start, exact, 0)
+	case mediatype == "text/html":
+		text, exact := decode(header, body)
+		text = reBlockquote.ReplaceAllStringFunc(text, blank)
+		return rebase(htmlDoc(text), start, exact, 0)
+	}
+	return nil
+}
+
+// multipart splits a multipart body into its parts, given the boundary.
+func multipart(body, boundary string) []region {
+	if boundary == "" {
+		return nil
+	}
+
+	var regions []region
+	ls := lines(body)
+	for i, l := range ls {
+		text := strings.TrimRight(l.text, " \t")
+		if text != "--"+boundary && text != "--"+boundary+"--" {
+			continue
+		}
+		if len(regions) > 0 {
+			// The line break before the boundary belongs to the boundary.
+			end := l.start
+			if end > 0 && body[end-1] == '\n' {
+				end--
+			}
+			if end > 0 && body[end-1] == '\r' {
+				end--
+			}
+			regions[len(regions)-1].end = end
+		}
+		if text == "--"+boundary+"--" {
+			break
+		}
+		start := len(body)
+		if i+1 < len(ls) {
+			start = ls[i+1].start
+		}
+		regions = append(regions, region{start, len(body)})
+	}
+	return regions
+}
+
+// decode decodes a body with its content transfer encoding and charset, and determines if
+// offsets within the decoded body match those of the original.
+func decode(header mail.Header, body string) (string, bool) {
+	text, exact := body, true
+	switch strings.ToLower(strings.TrimSpace(header.Get("Content-Transfer-Encoding"))) {
+	case "quoted-printable":
+		if dec, err := io.ReadAll(quotedprintable.NewReader(strings.NewReader(body))); err == nil {
+			text, exact = string(dec), false
+		}
+	case "base64":
+		if dec, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(body), "")); err == nil {
+			text, exact = string(dec), false
+		}
+	}
+
+	// Bodies in other charsets (such as ISO-8859-1 or Windows-1252) are converted to UTF-8.
+	_, params, _ := mime.ParseMediaType(header.Get("Content-Type"))
+	if enc, err := htmlindex.Get(params["charset"]); err == nil && enc != unicode.UTF8 {
+		if dec, err := enc.NewDecoder().String(text); err == nil {
+			exact = exact && dec == text
+			text = dec
+		}
+	}
+	return text, exact
+}
+
+// charsetReader converts text in the given charset to UTF-8, for decoding headers.
+func charsetReader(charset string, input io.Reader) (io.Reader, error) {
+	enc, err := htmlindex.Get(charset)
+	if err != nil {
+		return nil, err
+	}
+	return enc.NewDecoder().Reader(input), nil
+}
+
+// clean blanks out the greeting, quoted replies, forwarded history, signatures, sign-offs and
+// disclaimers of a plain-text body, keeping the offsets of the remaining text intact.
+func clean(body string) string {
+	ls := lines(body)
+	drop := make([]bool, len(ls))
+	greet := make([]int, len(ls)) // The length of the greeting starting the line (if any).
+	cut := len(ls)
+
+	greeting := true
+	for i, l := range ls {
+		text := strings.TrimSpace(l.text)
+		next := ""
+		if i+1 < len(ls) {
+			next = ls[i+1].text
+		}
+
+		// Everything following a signature delimiter, or forwarded history, is removed.
+		if l.text == "-- " || text == "--" || reForwarded.MatchString(l.text) ||
+			(strings.HasPrefix(text, "From:") && reOutlook.MatchString(next)) {
+			cut = i
+			break
+		}
+
+		switch {
+		case strings.HasPrefix(text, ">"), reAttribution.MatchString(l.text), reMobile.MatchString(l.text):
+			drop[i] = true
+		case greeting && reGreeting.MatchString(l.text):
+			// Only the opening line may be a greeting (e.g. "Hi team,"). The text following
+			// a greeting on the same line (e.g. "Hi all, the build is broken") is kept.
+			greet[i] = len(reGreeting.FindString(l.text))
+			drop[i] = greet[i] == len(l.text)
+		case reAttrStart.MatchString(l.text) && reWrote.MatchString(next):
+			// An attribution wrapped onto two lines.
+			drop[i], drop[i+1] = true, true
+		}
+		greeting = greeting && l.blank()
+	}
+
+	// Disclaimers are removed paragraph by paragraph.
+	for i := 0; i < cut; i++ {
+		if drop[i] || !reDisclaimer.MatchString(ls[i].text) || (i > 0 && !ls[i-1].blank()) {
+			continue
+		}
+		for ; i < cut && !ls[i].blank(); i++ {
+			drop[i] = true
+		}
+	}
+
+	// A sign-off (e.g. "Thanks," followed by the sender's name) near the end is removed.
+	remaining := 0
+	for i := cut - 1; i >= 0 && remaining <= signOffLines; i-- {
+		if drop[i] || ls[i].blank() {
+			continue
+		}
+		if reSignOff.MatchString(ls[i].text) {
+			cut = i
+			break
+		}
+		remaining++
+	}
+
+	cleaned := []byte(body)
+	for i, l := range ls {
+		if i >= cut || drop[i] {
+			copy(cleaned[l.start:], blank(l.text))
+		} else if greet[i] > 0 {
+			copy(cleaned[l.start:], blank(l.text[:greet[i]]))
+		}
+	}
+	return string(cleaned)
+}
+
+// blank replaces each byte of the text with a space, keeping line breaks.
+func blank(text string) string {
+	b := []byte(text)
+	for i := range b {
+		if b[i] != '\n' {
+			b[i] = ' '
+		}
+	}
+	return string(b)
+}
+
+// rebase shifts the offsets of the units by start, or maps them all to start if the units'
+// text was decoded, and shifts their paragraphs by para.
+func rebase(units []unit, start int, exact bool, para int) []unit {
+	for i := range units {
+		offsets := make([]int, len(units[i].offsets))
+		for j, off := range units[i].offsets {
+			offsets[j] = start
+			if exact {
+				offsets[j] += off
+			}
+		}
+		units[i].offsets = offsets
+		units[i].paragraph += para
+	}
+	return units
+}
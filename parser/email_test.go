@@ -0,0 +1,91 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/algao1/basically"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClean(t *testing.T) {
+	cases := []struct {
+		name, body string
+		kept       []string
+	}{
+		{
+			"greeting line",
+			"Hi team,\n\nThe build is fixed.\n",
+			[]string{"The build is fixed."},
+		},
+		{
+			"greeting before text",
+			"Hi all, the build is broken again\n",
+			[]string{"the build is broken again"},
+		},
+		{
+			"quoted reply",
+			"Sounds good to me.\n\nOn Mon, 1 Feb 2021 at 10:00, Bob <bob@example.com> wrote:\n> Shall we ship it?\n> It passed review.\n",
+			[]string{"Sounds good to me."},
+		},
+		{
+			"sign-off",
+			"The report is attached.\n\nThanks,\nAlice\nHead of Research\n",
+			[]string{"The report is attached."},
+		},
+		{
+			"signature",
+			"See you tomorrow.\n\n-- \nAlice Smith\nExample Corp\n",
+			[]string{"See you tomorrow."},
+		},
+	}
+
+	for _, c := range cases {
+		cleaned := clean(c.body)
+		assert.Len(t, cleaned, len(c.body), c.name)
+		assert.Equal(t, c.kept, texts(structure(cleaned)), c.name)
+	}
+}
+
+func TestEmailMIME(t *testing.T) {
+	doc := "From: Alice Smith <alice@example.com>\r\n" +
+		"Subject: =?iso-8859-1?q?Caf=E9_menu?=\r\n" +
+		"Date: Mon, 1 Feb 2021 10:00:00 +0000\r\n" +
+		"MIME-Version: 1.0\r\n" +
+		"Content-Type: multipart/alternative; boundary=\"b1\"\r\n" +
+		"\r\n" +
+		"--b1\r\n" +
+		"Content-Type: text/plain; charset=iso-8859-1\r\n" +
+		"Content-Transfer-Encoding: quoted-printable\r\n" +
+		"\r\n" +
+		"The caf=E9 now serves cr=E8me br=FBl=E9e.\r\n" +
+		"--b1\r\n" +
+		"Content-Type: text/html; charset=utf-8\r\n" +
+		"\r\n" +
+		"<p>The HTML version.</p>\r\n" +
+		"--b1--\r\n"
+
+	units := email(doc)
+	assert.Equal(t, []string{"Café menu", "The café now serves crème brûlée."}, texts(units))
+	for _, u := range units {
+		assert.True(t, utf8.ValidString(u.text), u.text)
+		assert.Equal(t, "Alice Smith", u.speaker)
+		assert.Equal(t, 2021, u.date.Year())
+	}
+	if assert.NotEmpty(t, units) {
+		assert.Equal(t, basically.Heading, units[0].kind)
+	}
+
+	// Bodies in Windows-1252 are converted, without transfer encoding.
+	doc = "From: bob@example.com\n" +
+		"Content-Type: text/plain; charset=windows-1252\n" +
+		"\n" +
+		"It costs \x8050, and that\x92s final.\n"
+	units = email(doc)
+	assert.Equal(t, []string{"It costs €50, and that’s final."}, texts(units))
+	if assert.Len(t, units, 1) {
+		assert.Equal(t, "bob@example.com", units[0].speaker)
+		assert.True(t, strings.HasPrefix(doc[units[0].offsets[0]:], "It costs"))
+	}
+}
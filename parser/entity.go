@@ -0,0 +1,165 @@
+package parser
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/algao1/basically/internal/prose"
+)
+
+// An EntityAnnotation is a text annotated with named-entity spans, such as
+// the output of the Prodigy annotation tool.
+type EntityAnnotation struct {
+	Text   string       // The annotated text.
+	Spans  []EntitySpan // The entity locations relative to Text.
+	Accept bool         // Whether the annotator accepted the spans as correct.
+}
+
+// An EntitySpan is a labelled span of text, measured in characters.
+type EntitySpan struct {
+	Start int    // The offset of the first character.
+	End   int    // The offset following the last character.
+	Label string // The entity's type.
+}
+
+// An EntityScore holds the evaluation results for a single entity type.
+type EntityScore struct {
+	Label     string  // The entity's type.
+	Precision float64 // Fraction of predicted entities that are correct.
+	Recall    float64 // Fraction of annotated entities that are predicted.
+	Support   int     // Number of annotated entities.
+}
+
+// An EntityModel is a maximum entropy named-entity extractor.
+type EntityModel struct {
+	model *prose.Model
+}
+
+// ReadProdigy reads Prodigy-style JSONL annotations, with one annotated text per line.
+// Annotations without an answer are treated as accepted.
+func ReadProdigy(data []byte) ([]EntityAnnotation, error) {
+	type prodigyLine struct {
+		Text  string `json:"text"`
+		Spans []struct {
+			Start int    `json:"start"`
+			End   int    `json:"end"`
+			Label string `json:"label"`
+		} `json:"spans"`
+		Answer string `json:"answer"`
+	}
+
+	var annots []EntityAnnotation
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+
+		var entry prodigyLine
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		annot := EntityAnnotation{Text: entry.Text, Accept: entry.Answer == "" || entry.Answer == "accept"}
+		for _, span := range entry.Spans {
+			annot.Spans = append(annot.Spans, EntitySpan{Start: span.Start, End: span.End, Label: span.Label})
+		}
+		annots = append(annots, annot)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to read annotations", err)
+	}
+	return annots, nil
+}
+
+// TrainEntities trains a new EntityModel on the given annotations.
+func TrainEntities(name string, annots []EntityAnnotation) *EntityModel {
+	data := make([]prose.EntityContext, 0, len(annots))
+	for _, annot := range annots {
+		spans := make([]prose.LabeledEntity, 0, len(annot.Spans))
+		for _, span := range annot.Spans {
+			spans = append(spans, prose.LabeledEntity{Start: span.Start, End: span.End, Label: span.Label})
+		}
+		data = append(data, prose.EntityContext{Text: annot.Text, Spans: spans, Accept: annot.Accept})
+	}
+
+	return &EntityModel{model: prose.ModelFromData(name, prose.UsingEntities(data))}
+}
+
+// EntitiesFromDisk loads an EntityModel from the user-provided location.
+func EntitiesFromDisk(path string) (*EntityModel, error) {
+	model, err := prose.LoadModel(path)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to load entity model", err)
+	}
+	return &EntityModel{model: model}, nil
+}
+
+// Write saves an EntityModel to the user-provided location.
+func (m *EntityModel) Write(path string) error {
+	if err := m.model.Write(path); err != nil {
+		return fmt.Errorf("%q: %w", "unable to write entity model", err)
+	}
+	return nil
+}
+
+// Evaluate computes the precision and recall of the model for each entity type,
+// against the given (held-out) annotations. Predicted entities must match the
+// annotated spans exactly. Rejected annotations are ignored.
+func (m *EntityModel) Evaluate(annots []EntityAnnotation) []EntityScore {
+	tp := make(map[string]int)
+	fp := make(map[string]int)
+	fn := make(map[string]int)
+
+	for _, annot := range annots {
+		if !annot.Accept {
+			continue
+		}
+
+		gold := make(map[EntitySpan]bool, len(annot.Spans))
+		for _, span := range annot.Spans {
+			gold[span] = true
+		}
+
+		for _, ent := range m.model.Locate(annot.Text) {
+			span := EntitySpan{Start: ent.Start, End: ent.End, Label: ent.Label}
+			if gold[span] {
+				tp[span.Label]++
+				delete(gold, span)
+			} else {
+				fp[span.Label]++
+			}
+		}
+
+		for span := range gold {
+			fn[span.Label]++
+		}
+	}
+
+	labels := make(map[string]struct{})
+	for _, counts := range []map[string]int{tp, fp, fn} {
+		for label := range counts {
+			labels[label] = struct{}{}
+		}
+	}
+
+	scores := make([]EntityScore, 0, len(labels))
+	for label := range labels {
+		score := EntityScore{Label: label, Support: tp[label] + fn[label]}
+		if n := tp[label] + fp[label]; n > 0 {
+			score.Precision = float64(tp[label]) / float64(n)
+		}
+		if score.Support > 0 {
+			score.Recall = float64(tp[label]) / float64(score.Support)
+		}
+		scores = append(scores, score)
+	}
+
+	sort.Slice(scores, func(i, j int) bool { return scores[i].Label < scores[j].Label })
+	return scores
+}
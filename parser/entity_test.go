@@ -0,0 +1,78 @@
+package parser
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadProdigy(t *testing.T) {
+	data := `{"text": "I love my iPhone.", "spans": [{"start": 10, "end": 16, "label": "PRODUCT"}], "answer": "accept"}
+
+{"text": "Nothing here.", "spans": [], "answer": "reject"}
+{"text": "The Pixel 4 is out.", "spans": [{"start": 4, "end": 11, "label": "PRODUCT"}]}
+`
+	annots, err := ReadProdigy([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []EntityAnnotation{
+		{Text: "I love my iPhone.", Spans: []EntitySpan{{Start: 10, End: 16, Label: "PRODUCT"}}, Accept: true},
+		{Text: "Nothing here.", Accept: false},
+		{Text: "The Pixel 4 is out.", Spans: []EntitySpan{{Start: 4, End: 11, Label: "PRODUCT"}}, Accept: true},
+	}, annots)
+
+	_, err = ReadProdigy([]byte(`{"text": "ok"}` + "\n" + `{"text": `))
+	assert.EqualError(t, err, "line 2: unexpected end of JSON input")
+}
+
+func TestEntityModel(t *testing.T) {
+	data, err := ioutil.ReadFile("../internal/prose/testdata/reddit_product.jsonl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	annots, err := ReadProdigy(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A subset of the annotations keeps training quick.
+	train, test := annots[:150], annots[150:200]
+
+	model := TrainEntities("PRODUCT", train)
+	scores := model.Evaluate(test)
+	if assert.Len(t, scores, 1) {
+		assert.Equal(t, "PRODUCT", scores[0].Label)
+		assert.Greater(t, scores[0].Support, 0)
+		assert.Greater(t, scores[0].Precision, 0.0)
+		assert.Greater(t, scores[0].Recall, 0.0)
+	}
+
+	// A model written to disk extracts the same entities once loaded.
+	path := filepath.Join(t.TempDir(), "PRODUCT")
+	if err := model.Write(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := EntitiesFromDisk(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, scores, loaded.Evaluate(test))
+
+	_, err = EntitiesFromDisk(filepath.Join(t.TempDir(), "missing"))
+	assert.Error(t, err)
+}
+
+func TestEvaluate(t *testing.T) {
+	model := TrainEntities("PRODUCT", []EntityAnnotation{
+		{Text: "I love my iPhone.", Spans: []EntitySpan{{Start: 10, End: 16, Label: "PRODUCT"}}, Accept: true},
+	})
+
+	// Rejected annotations are ignored, and unmatched spans count against recall.
+	scores := model.Evaluate([]EntityAnnotation{
+		{Text: "I love my iPhone.", Spans: []EntitySpan{{Start: 0, End: 1, Label: "PRODUCT"}}, Accept: false},
+		{Text: "Nothing to see.", Spans: []EntitySpan{{Start: 0, End: 7, Label: "PRODUCT"}}, Accept: true},
+	})
+	assert.Equal(t, []EntityScore{{Label: "PRODUCT", Precision: 0, Recall: 0, Support: 1}}, scores)
+}
@@ -0,0 +1,522 @@
+package parser
+
+import (
+	"html"
+	"math"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/algao1/basically"
+)
+
+// An element is a node of a (loosely parsed) HTML document. Text nodes have no tag.
+type element struct {
+	tag      string
+	attrs    string // The element's raw attributes.
+	text     string // The text of a text node.
+	start    int    // The offset of the element (or text) in the original document.
+	parent   *element
+	children []*element
+}
+
+var (
+	reTagName  = regexp.MustCompile(`^</?([a-zA-Z][a-zA-Z0-9-]*)`)
+	reEntity   = regexp.MustCompile(`&(?:#[0-9]+|#[xX][0-9a-fA-F]+|[a-zA-Z][a-zA-Z0-9]*);`)
+	reClassID  = regexp.MustCompile(`(?i)(?:class|id)\s*=\s*(?:"[^"]*"|'[^']*'|[^\s>]+)`)
+	reNegative = regexp.MustCompile(`(?i)cookie|consent|banner|nav|menu|footer|sidebar|share|social|comment|promo|advert|sponsor|related|subscribe|newsletter|popup|modal|breadcrumb|masthead|widget`)
+	rePositive = regexp.MustCompile(`(?i)article|body|content|entry|main|post|story|text`)
+)
+
+var (
+	voidTags  = tagSet("area base br col embed hr img input link meta param source track wbr")
+	rawTags   = tagSet("script style textarea title")
+	dropTags  = tagSet("script style noscript template svg nav footer aside form button iframe select object canvas dialog")
+	blockTags = tagSet("address article aside blockquote dd details div dl dt fieldset figcaption figure footer " +
+		"h1 h2 h3 h4 h5 h6 header hr li main nav ol p pre section summary table tbody td tfoot th thead tr ul")
+	headingTags = tagSet("h1 h2 h3 h4 h5 h6")
+)
+
+func tagSet(tags string) map[string]bool {
+	set := make(map[string]bool)
+	for _, tag := range strings.Fields(tags) {
+		set[tag] = true
+	}
+	return set
+}
+
+// htmlDoc splits an HTML document into units. The main content is found using a
+// readability-style heuristic: paragraphs are scored by their length and number of commas,
+// and their scores are propagated to their ancestors. The highest-scoring element (after
+// penalizing link-heavy elements), along with any similarly scored siblings, is kept.
+// Scripts, styles, navigation, footers and other boilerplate are dropped. The page title
+// and the content's headings are kept as headings.
+func htmlDoc(doc string) []unit {
+	root := parseHTML(doc)
+	ex := &extractor{}
+
+	// The title is kept unless repeated by the content's first heading.
+	title := find(root, "title")
+	content := mainContent(root)
+	if title != nil {
+		var heading *element
+		for _, e := range content {
+			if heading = findFunc(e, func(e *element) bool { return headingTags[e.tag] }); heading != nil {
+				break
+			}
+		}
+		if heading == nil || !strings.EqualFold(innerText(heading), innerText(title)) {
+			ex.heading(title, 1)
+		}
+	}
+
+	for _, e := range content {
+		ex.walk(e)
+	}
+	ex.flush()
+
+	return ex.units
+}
+
+// parseHTML leniently parses an HTML document into a tree of elements. Unclosed paragraphs,
+// list items and table cells are closed implicitly, and unmatched end tags are ignored.
+func parseHTML(doc string) *element {
+	root := &element{tag: "#root"}
+	cur := root
+	text := func(start, end int) {
+		if start < end {
+			cur.children = append(cur.children, &element{text: doc[start:end], start: start, parent: cur})
+		}
+	}
+
+	for i := 0; i < len(doc); {
+		lt := strings.IndexByte(doc[i:], '<')
+		if lt < 0 {
+			text(i, len(doc))
+			break
+		}
+		text(i, i+lt)
+		i += lt
+
+		// Skip comments, doctypes and processing instructions.
+		if strings.HasPrefix(doc[i:], "<!--") {
+			end := strings.Index(doc[i+4:], "-->")
+			if end < 0 {
+				break
+			}
+			i += 4 + end + 3
+			continue
+		}
+		if strings.HasPrefix(doc[i:], "<!") || strings.HasPrefix(doc[i:], "<?") {
+			i = tagEnd(doc, i)
+			continue
+		}
+
+		m := reTagName.FindStringSubmatch(doc[i:])
+		if m == nil {
+			text(i, i+1)
+			i++
+			continue
+		}
+		name := strings.ToLower(m[1])
+		end := tagEnd(doc, i)
+
+		// Close the nearest open element with the same name (if any).
+		if doc[i+1] == '/' {
+			for e := cur; e != root; e = e.parent {
+				if e.tag == name {
+					cur = e.parent
+					break
+				}
+			}
+			i = end
+			continue
+		}
+
+		cur = implicitClose(cur, name)
+		attrs := strings.TrimSuffix(doc[i+len(m[0]):end], ">")
+		e := &element{tag: name, attrs: attrs, start: i, parent: cur}
+		cur.children = append(cur.children, e)
+		i = end
+
+		switch {
+		case voidTags[name] || strings.HasSuffix(strings.TrimSpace(attrs), "/"):
+		case rawTags[name]:
+			// Raw text elements end at their end tag, regardless of their content.
+			close := strings.Index(strings.ToLower(doc[i:]), "</"+name)
+			if close < 0 {
+				close = len(doc) - i
+			}
+			e.children = append(e.children, &element{text: doc[i : i+close], start: i, parent: e})
+			i = tagEnd(doc, i+close)
+		default:
+			cur = e
+		}
+	}
+
+	return root
+}
+
+// tagEnd returns the offset following the end of the tag starting at i.
+func tagEnd(doc string, i int) int {
+	var quote byte
+	for j := i + 1; j < len(doc); j++ {
+		switch {
+		case quote != 0:
+			if doc[j] == quote {
+				quote = 0
+			}
+		case doc[j] == '"' || doc[j] == '\'':
+			quote = doc[j]
+		case doc[j] == '>':
+			return j + 1
+		}
+	}
+	return len(doc)
+}
+
+// implicitClose closes the open elements that cannot contain an element with the given name,
+// such as an open paragraph when a list starts, or an open list item when the next one starts.
+func implicitClose(cur *element, name string) *element {
+	for {
+		closed := false
+		for e := cur; e.parent != nil; e = e.parent {
+			if closes(e.tag, name) {
+				cur, closed = e.parent, true
+				break
+			}
+			// Only look past inline elements.
+			if blockTags[e.tag] {
+				break
+			}
+		}
+		if !closed {
+			return cur
+		}
+	}
+}
+
+// closes determines if an open element is implicitly closed by the start of another element.
+func closes(open, name string) bool {
+	switch open {
+	case "p":
+		return blockTags[name]
+	case "li":
+		return name == "li"
+	case "dt", "dd":
+		return name == "dt" || name == "dd"
+	case "td", "th":
+		return name == "td" || name == "th" || name == "tr"
+	case "tr":
+		return name == "tr"
+	case "option":
+		return name == "option"
+	}
+	return false
+}
+
+// dropped determines if the element is boilerplate, based on its tag, class and id.
+func dropped(e *element) bool {
+	switch {
+	case dropTags[e.tag]:
+		return true
+	case e.tag == "header":
+		// Headers within the content (e.g. an article's header) are kept.
+		return find(e, "h1") == nil || ancestor(e, "article", "main") == nil
+	case e.tag == "body", e.tag == "html", e.tag == "article", e.tag == "main":
+		return false
+	}
+
+	classID := strings.Join(reClassID.FindAllString(e.attrs, -1), " ")
+	return classID != "" && reNegative.MatchString(classID) && !rePositive.MatchString(classID)
+}
+
+// mainContent returns the elements making up the main content of the document.
+func mainContent(root *element) []*element {
+	scores := make(map[*element]float64)
+	var order []*element
+	score := func(e *element, s float64) {
+		if _, ok := scores[e]; !ok {
+			scores[e] = initialScore(e)
+			order = append(order, e)
+		}
+		scores[e] += s
+	}
+
+	var visit func(e *element)
+	visit = func(e *element) {
+		if dropped(e) {
+			return
+		}
+		if e.tag == "p" || e.tag == "pre" || e.tag == "td" || (e.tag == "div" && !hasBlock(e)) {
+			text := innerText(e)
+			if n := len(text); n >= 25 && e.parent != nil {
+				s := 1 + float64(strings.Count(text, ",")) + math.Min(float64(n/100), 3)
+				score(e.parent, s)
+				if e.parent.parent != nil {
+					score(e.parent.parent, s/2)
+				}
+			}
+		}
+		for _, c := range e.children {
+			visit(c)
+		}
+	}
+	visit(root)
+
+	var top *element
+	for _, e := range order {
+		scores[e] *= 1 - linkDensity(e)
+		if top == nil || scores[e] > scores[top] {
+			top = e
+		}
+	}
+	if top == nil {
+		if body := find(root, "body"); body != nil {
+			return []*element{body}
+		}
+		return []*element{root}
+	}
+	if top.parent == nil {
+		return []*element{top}
+	}
+
+	// Siblings with similar scores, or long paragraphs with few links, are also kept.
+	threshold := math.Max(10, scores[top]*0.2)
+	var content []*element
+	for _, sib := range top.parent.children {
+		s, ok := scores[sib]
+		switch {
+		case sib == top, ok && s >= threshold:
+			content = append(content, sib)
+		case sib.tag == "p" && !dropped(sib) && len(innerText(sib)) > 80 && linkDensity(sib) < 0.25:
+			content = append(content, sib)
+		}
+	}
+	return content
+}
+
+// initialScore scores an element based on its tag, class and id.
+func initialScore(e *element) float64 {
+	var s float64
+	switch e.tag {
+	case "article", "main":
+		s = 10
+	case "div":
+		s = 5
+	case "pre", "td", "blockquote":
+		s = 3
+	case "address", "ol", "ul", "dl", "dd", "dt", "li", "form":
+		s = -3
+	case "h1", "h2", "h3", "h4", "h5", "h6", "th":
+		s = -5
+	}
+
+	classID := strings.Join(reClassID.FindAllString(e.attrs, -1), " ")
+	if reNegative.MatchString(classID) {
+		s -= 25
+	}
+	if rePositive.MatchString(classID) {
+		s += 25
+	}
+	return s
+}
+
+// linkDensity returns the fraction of the element's text that is within links.
+func linkDensity(e *element) float64 {
+	total := len(innerText(e))
+	if total == 0 {
+		return 0
+	}
+
+	var links int
+	var visit func(e *element)
+	visit = func(e *element) {
+		if e.tag == "a" {
+			links += len(innerText(e))
+			return
+		}
+		for _, c := range e.children {
+			visit(c)
+		}
+	}
+	visit(e)
+
+	return float64(links) / float64(total)
+}
+
+// innerText returns the element's text (excluding boilerplate), with whitespace collapsed.
+func innerText(e *element) string {
+	var sb strings.Builder
+	var visit func(e *element)
+	visit = func(e *element) {
+		if e.tag == "" {
+			sb.WriteString(html.UnescapeString(e.text))
+			sb.WriteByte(' ')
+			return
+		}
+		if dropped(e) {
+			return
+		}
+		for _, c := range e.children {
+			visit(c)
+		}
+	}
+	visit(e)
+	return strings.Join(strings.Fields(sb.String()), " ")
+}
+
+// hasBlock determines if any of the element's children are block-level elements.
+func hasBlock(e *element) bool {
+	for _, c := range e.children {
+		if blockTags[c.tag] {
+			return true
+		}
+	}
+	return false
+}
+
+// find returns the first element with the given tag, in document order.
+func find(e *element, tag string) *element {
+	return findFunc(e, func(e *element) bool { return e.tag == tag })
+}
+
+// findFunc returns the first element satisfying f, in document order.
+func findFunc(e *element, f func(*element) bool) *element {
+	if f(e) {
+		return e
+	}
+	for _, c := range e.children {
+		if found := findFunc(c, f); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// ancestor returns the nearest ancestor with one of the given tags.
+func ancestor(e *element, tags ...string) *element {
+	for p := e.parent; p != nil; p = p.parent {
+		for _, tag := range tags {
+			if p.tag == tag {
+				return p
+			}
+		}
+	}
+	return nil
+}
+
+// An extractor converts the main content of an HTML document into units.
+type extractor struct {
+	units []unit
+	cur   *builder
+	kind  basically.Kind
+	para  int
+}
+
+// flush adds the current unit (if any).
+func (ex *extractor) flush() {
+	if ex.cur != nil {
+		if u, ok := ex.cur.unit(ex.kind, 0, ex.para); ok {
+			ex.units = append(ex.units, u)
+			if ex.kind != basically.Bullet {
+				ex.para++
+			}
+		}
+	}
+	ex.cur, ex.kind = nil, basically.Body
+}
+
+// heading adds the element's text as a heading.
+func (ex *extractor) heading(e *element, level int) {
+	ex.flush()
+	ex.cur = &builder{}
+	ex.text(e)
+	if u, ok := ex.cur.unit(basically.Heading, level, ex.para); ok {
+		ex.units = append(ex.units, u)
+		ex.para++
+	}
+	ex.cur = nil
+}
+
+// walk adds the text within the element, splitting it into units at block-level elements.
+func (ex *extractor) walk(e *element) {
+	switch {
+	case e.tag == "":
+		ex.write(e)
+	case dropped(e), e.tag == "pre", e.tag == "table", e.tag == "title":
+	case headingTags[e.tag]:
+		ex.heading(e, int(e.tag[1]-'0'))
+	case e.tag == "br":
+		if ex.cur != nil {
+			ex.cur.insert("\n", e.start)
+		}
+	case e.tag == "ul" || e.tag == "ol":
+		ex.flush()
+		for _, c := range e.children {
+			ex.walk(c)
+		}
+		ex.flush()
+		ex.para++
+	case e.tag == "li":
+		ex.flush()
+		ex.kind = basically.Bullet
+		for _, c := range e.children {
+			ex.walk(c)
+		}
+		ex.flush()
+	case blockTags[e.tag]:
+		ex.flush()
+		for _, c := range e.children {
+			ex.walk(c)
+		}
+		ex.flush()
+	default:
+		for _, c := range e.children {
+			ex.walk(c)
+		}
+	}
+}
+
+// text adds all the text within the element to the current unit.
+func (ex *extractor) text(e *element) {
+	if e.tag == "" {
+		ex.write(e)
+		return
+	}
+	for _, c := range e.children {
+		ex.text(c)
+	}
+}
+
+// write adds a text node to the current unit, decoding entities and collapsing whitespace.
+func (ex *extractor) write(e *element) {
+	if ex.cur == nil {
+		if strings.TrimSpace(e.text) == "" {
+			return
+		}
+		ex.cur = &builder{}
+	}
+
+	ents := reEntity.FindAllStringIndex(e.text, -1)
+	for i := 0; i < len(e.text); {
+		if len(ents) > 0 && ents[0][0] == i {
+			ex.cur.insert(html.UnescapeString(e.text[i:ents[0][1]]), e.start+i)
+			i = ents[0][1]
+			ents = ents[1:]
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(e.text[i:])
+		if unicode.IsSpace(r) {
+			s := ex.cur.sb.String()
+			if last, _ := utf8.DecodeLastRuneInString(s); s != "" && !unicode.IsSpace(last) {
+				ex.cur.insert(" ", e.start+i)
+			}
+		} else {
+			ex.cur.write(e.text[i:i+size], e.start+i)
+		}
+		i += size
+	}
+}
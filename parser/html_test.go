@@ -0,0 +1,116 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/algao1/basically"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTMLNonASCII(t *testing.T) {
+	doc := "<html><body><article><h1>Škoda in Åland</h1>\n" +
+		"<p>Voilà, the new Škoda  arrived in\nÅland.</p>\n" +
+		"<p>Prices start at 20&nbsp;000 €.</p></article></body></html>"
+
+	units := htmlDoc(doc)
+	texts := make([]string, 0, len(units))
+	for _, u := range units {
+		assert.True(t, utf8.ValidString(u.text), u.text)
+		texts = append(texts, u.text)
+	}
+	assert.Equal(t, []string{
+		"Škoda in Åland",
+		"Voilà, the new Škoda arrived in Åland.",
+		"Prices start at 20\u00a0000 €.",
+	}, texts)
+
+	if assert.NotEmpty(t, units) {
+		assert.Equal(t, basically.Heading, units[0].kind)
+		start, end := units[0].span(0, len(units[0].text))
+		assert.Equal(t, "Škoda in Åland", doc[start:end])
+	}
+	if assert.Len(t, units, 3) {
+		idx := strings.Index(units[1].text, "Škoda")
+		start, end := units[1].span(idx, idx+len("Škoda"))
+		assert.Equal(t, "Škoda", doc[start:end])
+	}
+}
+
+func TestHTMLBoilerplate(t *testing.T) {
+	doc := `<html><head><title>Council approves budget</title>
+<style>p { color: red; }</style><script>var p = "<p>Not text.</p>";</script></head>
+<body>
+<nav><a href="/">Home</a> <a href="/news">News</a></nav>
+<div class="cookie-banner"><p>We use cookies to improve your experience, to analyse traffic, and for ads.</p></div>
+<article>
+<p>The city council approved the new budget on Tuesday, after a long debate.</p>
+<p>Spending on parks, libraries and roads will rise next year, the mayor said.</p>
+</article>
+<footer><p>Copyright 2021, The City Paper. All rights reserved, everywhere.</p></footer>
+</body></html>`
+
+	assert.Equal(t, []string{
+		"Council approves budget",
+		"The city council approved the new budget on Tuesday, after a long debate.",
+		"Spending on parks, libraries and roads will rise next year, the mayor said.",
+	}, texts(htmlDoc(doc)))
+}
+
+func TestHTMLLinkDensity(t *testing.T) {
+	link := `<p><a href="/a">Read more about the budget, the council, and the city, in our archive</a></p>`
+	doc := `<html><body>
+<div id="a">` + strings.Repeat(link, 4) + `</div>
+<div id="b">
+<p>The city council approved the new budget on Tuesday, after a long debate.</p>
+<p>Spending on parks, libraries and roads will rise next year, the mayor said.</p>
+</div>
+</body></html>`
+
+	// The links score more (with more text and commas), but are penalized for their density.
+	assert.Equal(t, []string{
+		"The city council approved the new budget on Tuesday, after a long debate.",
+		"Spending on parks, libraries and roads will rise next year, the mayor said.",
+	}, texts(htmlDoc(doc)))
+}
+
+func TestHTMLTitle(t *testing.T) {
+	body := `<body><article><h1>%s</h1>
+<p>The city council approved the new budget on Tuesday, after a long debate.</p></article></body>`
+	cases := []struct {
+		title, heading string
+		texts          []string
+	}{
+		{"Budget approved", "Budget Approved", []string{"Budget Approved",
+			"The city council approved the new budget on Tuesday, after a long debate."}},
+		{"City Paper", "Budget approved", []string{"City Paper", "Budget approved",
+			"The city council approved the new budget on Tuesday, after a long debate."}},
+	}
+
+	for _, tc := range cases {
+		doc := "<html><head><title>" + tc.title + "</title></head>" + strings.Replace(body, "%s", tc.heading, 1) + "</html>"
+		units := htmlDoc(doc)
+		assert.Equal(t, tc.texts, texts(units), tc.title)
+		assert.Equal(t, basically.Heading, units[0].kind, tc.title)
+	}
+}
+
+func TestHTMLOffsets(t *testing.T) {
+	doc := `<html><head><title>Budget</title></head><body><article>
+<p>The council <b>approved</b> the budget.   It passed &amp; was signed.</p>
+</article></body></html>`
+
+	sents, _, err := Create(WithHTML()).ParseDocument(doc, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raws := make([]string, 0, len(sents))
+	spans := make([]string, 0, len(sents))
+	for _, sent := range sents {
+		raws = append(raws, sent.Raw)
+		spans = append(spans, doc[sent.Start:sent.End])
+	}
+	assert.Equal(t, []string{"Budget", "The council approved the budget.", "It passed & was signed."}, raws)
+	assert.Equal(t, []string{"Budget", "The council <b>approved</b> the budget.", "It passed &amp; was signed."}, spans)
+}
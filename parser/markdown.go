@@ -0,0 +1,183 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/algao1/basically"
+)
+
+var (
+	reFence    = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})")
+	reATX      = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+|$)`)
+	reATXClose = regexp.MustCompile(`(?:^|[ \t]+)#+[ \t]*$`)
+	reSetext   = regexp.MustCompile(`^ {0,3}(?:=+|-+)[ \t]*$`)
+	reRule     = regexp.MustCompile(`^ {0,3}(?:(?:-[ \t]*){3,}|(?:\*[ \t]*){3,}|(?:_[ \t]*){3,})$`)
+	reTableSep = regexp.MustCompile(`^[ \t]*\|?[ \t]*:?-+:?[ \t]*(?:\|[ \t]*:?-+:?[ \t]*)+\|?[ \t]*$`)
+	reQuote    = regexp.MustCompile(`^ {0,3}>[ \t]?`)
+	reLinkDef  = regexp.MustCompile(`^ {0,3}\[[^\]]+\]:[ \t]`)
+	reMDBullet = regexp.MustCompile(`^[ \t]*(?:[-*+]|\d{1,9}[.)])[ \t]+`)
+	reTask     = regexp.MustCompile(`^\[[ xX]\][ \t]+`)
+	reIndented = regexp.MustCompile(`^(?: {4}|\t)`)
+	reMDInline = regexp.MustCompile(strings.Join([]string{
+		`\[!\[[^\]]*\]\([^)]*\)\]\([^)]*\)`,      // Linked images (e.g. badges).
+		`!\[[^\]]*\](?:\([^)]*\)|\[[^\]]*\])`,    // Images.
+		`\[\^[^\]]+\]`,                           // Footnote references.
+		`\[([^\[\]]+)\](?:\([^)]*\)|\[[^\]]*\])`, // Links, keeping the link text.
+		`<(?:https?|mailto|ftp):[^>]+>`,          // Autolinks.
+		`<!--.*?-->|</?[a-zA-Z][^>]*>`,           // Inline HTML.
+		"(`+)([^`]+?)`+",                         // Inline code, keeping the code.
+		"\\\\([\\\\`*_{}\\[\\]()#+\\-.!|>~])",    // Escaped characters.
+		`\*\*\*([^\s*](?:.*?[^\s*])?)\*\*\*`,     // Emphasis, keeping the emphasized text.
+		`\*\*([^\s*](?:.*?[^\s*])?)\*\*`,
+		`\*([^\s*](?:.*?[^\s*])?)\*`,
+		`\b___([^\s_](?:.*?[^\s_])?)___\b`,
+		`\b__([^\s_](?:.*?[^\s_])?)__\b`,
+		`\b_([^\s_](?:.*?[^\s_])?)_\b`,
+		`~~([^\s~](?:.*?[^\s~])?)~~`, // Strikethrough.
+	}, "|"))
+)
+
+// markdown splits a Markdown document into units. Formatting is stripped, link text is kept,
+// and code blocks, tables, images and HTML comments are skipped. ATX ("#") and setext
+// headings record their level.
+func markdown(doc string) []unit {
+	var units []unit
+	var cur *builder
+	var fence string
+	kind, para, comment := basically.Body, 0, false
+
+	flush := func() {
+		if cur != nil {
+			if u, ok := cur.unit(kind, 0, para); ok {
+				units = append(units, u)
+			}
+		}
+		cur, kind = nil, basically.Body
+	}
+	// next flushes the current unit, and starts a new paragraph.
+	next := func() {
+		flush()
+		if len(units) > 0 && units[len(units)-1].paragraph == para {
+			para++
+		}
+	}
+	heading := func(l line, level int) {
+		next()
+		if loc := reATXClose.FindStringIndex(l.text); loc != nil {
+			l.text = l.text[:loc[0]]
+		}
+		b := &builder{}
+		inline(b, l.text, l.start)
+		if u, ok := b.unit(basically.Heading, level, para); ok {
+			units = append(units, u)
+		}
+		next()
+	}
+
+	ls := lines(doc)
+	for i := 0; i < len(ls); i++ {
+		l := ls[i]
+		trimmed := strings.TrimSpace(l.text)
+
+		switch {
+		case fence != "":
+			// Skip fenced code, until the closing fence.
+			if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+				fence = ""
+			}
+			continue
+		case comment:
+			comment = !strings.Contains(l.text, "-->")
+			continue
+		case reFence.MatchString(l.text):
+			next()
+			fence = reFence.FindStringSubmatch(l.text)[1]
+			continue
+		case strings.HasPrefix(trimmed, "<!--"):
+			next()
+			comment = !strings.Contains(trimmed, "-->")
+			continue
+		case l.blank():
+			next()
+			continue
+		case reATX.MatchString(l.text):
+			loc := reATX.FindStringSubmatchIndex(l.text)
+			heading(l.from(loc[1]), loc[3]-loc[2])
+			continue
+		case cur == nil && i+1 < len(ls) && reSetext.MatchString(ls[i+1].text) &&
+			!reMDBullet.MatchString(l.text) && !strings.Contains(l.text, "|"):
+			level := 1
+			if strings.Contains(ls[i+1].text, "-") {
+				level = 2
+			}
+			heading(l, level)
+			i++
+			continue
+		case reRule.MatchString(l.text):
+			next()
+			continue
+		case strings.Contains(l.text, "|") && i+1 < len(ls) && reTableSep.MatchString(ls[i+1].text):
+			// Skip tables, until the next blank line.
+			next()
+			for i+1 < len(ls) && !ls[i+1].blank() {
+				i++
+			}
+			continue
+		case reLinkDef.MatchString(l.text):
+			continue
+		case cur == nil && reIndented.MatchString(l.text) &&
+			(len(units) == 0 || units[len(units)-1].kind != basically.Bullet):
+			// Skip indented code, unless it continues a list item.
+			continue
+		}
+
+		// Strip (possibly nested) blockquote markers.
+		for loc := reQuote.FindStringIndex(l.text); loc != nil; loc = reQuote.FindStringIndex(l.text) {
+			l = l.from(loc[1])
+		}
+
+		if loc := reMDBullet.FindStringIndex(l.text); loc != nil {
+			flush()
+			l = l.from(loc[1])
+			if loc := reTask.FindStringIndex(l.text); loc != nil {
+				l = l.from(loc[1])
+			}
+			cur, kind = &builder{}, basically.Bullet
+		} else if cur == nil {
+			cur = &builder{}
+		} else {
+			cur.insert("\n", l.start)
+		}
+		inline(cur, l.text, l.start)
+	}
+	flush()
+
+	return units
+}
+
+// inline writes the text with its inline Markdown formatting removed.
+func inline(b *builder, text string, start int) {
+	last := 0
+	for _, m := range reMDInline.FindAllStringSubmatchIndex(text, -1) {
+		b.write(text[last:m[0]], start+last)
+		switch {
+		case m[2] >= 0:
+			inline(b, text[m[2]:m[3]], start+m[2])
+		case m[6] >= 0:
+			b.write(text[m[6]:m[7]], start+m[6])
+		case m[8] >= 0:
+			b.write(text[m[8]:m[9]], start+m[8])
+		default:
+			// Emphasis markers are removed in pairs, and may be nested.
+			for g := 10; g+1 < len(m); g += 2 {
+				if m[g] >= 0 {
+					inline(b, text[m[g]:m[g+1]], start+m[g])
+					break
+				}
+			}
+		}
+		last = m[1]
+	}
+	b.write(text[last:], start+last)
+}
@@ -0,0 +1,71 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/algao1/basically"
+	"github.com/stretchr/testify/assert"
+)
+
+// texts returns the text of each unit.
+func texts(units []unit) []string {
+	ret := make([]string, 0, len(units))
+	for _, u := range units {
+		ret = append(ret, u.text)
+	}
+	return ret
+}
+
+func TestMarkdown(t *testing.T) {
+	doc := "# Release *notes* #\n\n" +
+		"The **new** parser is _much_ faster, and 2 * 3 * 4 is still 24.\n" +
+		"Variables such as snake_case_name and a__b keep their underscores.\n\n" +
+		"Overview\n" +
+		"--------\n\n" +
+		"See the [docs](https://example.com) and ![logo](logo.png) `go build` ~~now~~.\n\n" +
+		"```go\n" +
+		"fmt.Println(\"skipped\")\n" +
+		"```\n\n" +
+		"| a | b |\n" +
+		"|---|---|\n" +
+		"| 1 | 2 |\n\n" +
+		"> Quoted **and *nested* emphasis**.\n\n" +
+		"- [x] Task done.\n" +
+		"* Unpaired * stars and \\*escapes\\*.\n\n" +
+		"<!-- a comment -->\n" +
+		"Last paragraph.\n"
+
+	units := markdown(doc)
+	kinds := make([]basically.Kind, 0, len(units))
+	levels := make([]int, 0, len(units))
+	for _, u := range units {
+		kinds = append(kinds, u.kind)
+		levels = append(levels, u.level)
+	}
+
+	assert.Equal(t, []string{
+		"Release notes",
+		"The new parser is much faster, and 2 * 3 * 4 is still 24.\n" +
+			"Variables such as snake_case_name and a__b keep their underscores.",
+		"Overview",
+		"See the docs and  go build now.",
+		"Quoted and nested emphasis.",
+		"Task done.",
+		"Unpaired * stars and *escapes*.",
+		"Last paragraph.",
+	}, texts(units))
+	assert.Equal(t, []basically.Kind{
+		basically.Heading, basically.Body, basically.Heading, basically.Body,
+		basically.Body, basically.Bullet, basically.Bullet, basically.Body,
+	}, kinds)
+	assert.Equal(t, []int{1, 0, 2, 0, 0, 0, 0, 0}, levels)
+
+	// Offsets point back to the original text.
+	for _, u := range units {
+		for idx := range u.text {
+			if u.text[idx] != '\n' {
+				assert.Equal(t, u.text[idx], doc[u.offsets[idx]], u.text)
+			}
+		}
+	}
+}
@@ -8,60 +8,241 @@ import (
 	"github.com/jonreiter/govader"
 )
 
+// A Config represents a setting that changes the parsing process.
+// For example, it may configure a custom Punkt model:
+//
+//	p := parser.Create(parser.WithPunktModel(model))
+type Config func(cfgs *Configs)
+
+// Configs control the parsing process.
+type Configs struct {
+	punkt     *PunktModel             // Default uses the bundled English Punkt model.
+	tagger    *TaggerModel            // Default uses the bundled English POS tagger.
+	entities  bool                    // Default disables named-entity extraction.
+	extracter *EntityModel            // Default uses the bundled English entity model.
+	format    func(doc string) []unit // Default parses documents as plain text.
+}
+
+// WithPunktModel sets the Punkt model used for sentence segmentation.
+func WithPunktModel(model *PunktModel) Config {
+	return func(cfgs *Configs) { cfgs.punkt = model }
+}
+
+// WithTagger sets the part-of-speech tagger used for tokens.
+func WithTagger(model *TaggerModel) Config {
+	return func(cfgs *Configs) { cfgs.tagger = model }
+}
+
+// WithEntities enables named-entity extraction, labelling tokens and
+// sentences with the entities they contain.
+func WithEntities() Config {
+	return func(cfgs *Configs) { cfgs.entities = true }
+}
+
+// WithEntityModel enables named-entity extraction using a custom entity model.
+func WithEntityModel(model *EntityModel) Config {
+	return func(cfgs *Configs) {
+		cfgs.entities = true
+		cfgs.extracter = model
+	}
+}
+
+// WithMarkdown parses documents as Markdown. Formatting is stripped, link text is kept,
+// and code blocks and tables are skipped. Sentence offsets refer to the original Markdown.
+func WithMarkdown() Config {
+	return func(cfgs *Configs) { cfgs.format = markdown }
+}
+
+// WithHTML parses documents as HTML web pages. The main content is extracted, and scripts,
+// styles, navigation and other boilerplate are dropped. The page title and headings are kept.
+// Sentence offsets refer to the original HTML.
+func WithHTML() Config {
+	return func(cfgs *Configs) { cfgs.format = htmlDoc }
+}
+
+// WithSubtitles parses documents as SRT or WebVTT subtitles. Cues are joined together
+// before sentence segmentation, and each sentence records the times of its cues. Long pauses
+// between cues start new paragraphs.
+func WithSubtitles() Config {
+	return func(cfgs *Configs) { cfgs.format = subtitles }
+}
+
+// WithTranscript parses documents as plain-text transcripts, where each turn starts with
+// the name of its speaker (e.g. "Alice: Let's get started."). The speaker is recorded on
+// each sentence of the turn, and lines without a speaker continue the previous turn. Names which
+// start a single turn (such as "Note:") are not taken as speakers.
+func WithTranscript() Config {
+	return func(cfgs *Configs) { cfgs.format = transcript }
+}
+
+// WithEmail parses documents as email messages (RFC 5322), or mailboxes (mbox) of messages.
+// The text parts of each message are kept, while quoted replies, forwarded history, signatures
+// and disclaimers are removed. Each sentence records the sender, date and order of its message.
+func WithEmail() Config {
+	return func(cfgs *Configs) { cfgs.format = email }
+}
+
 type Parser struct {
 	sentTokenizer *prose.PunktSentenceTokenizer
 	wordTokenizer *prose.IterTokenizer
 	tagger        *prose.PerceptronTagger
+	extracter     *prose.Model
 	analyzer      *govader.SentimentIntensityAnalyzer
+	format        func(doc string) []unit
 }
 
 var _ basically.Parser = (*Parser)(nil)
 
 // Create initializes the tokenizers, tagger, and sentiment analyzer.
-// Token classification is disabled for performance speed-up.
-func Create() *Parser {
+// Token classification is disabled for performance speed-up, unless
+// enabled by WithEntities.
+func Create(cfgs ...Config) *Parser {
+	var configs Configs
+	for _, applyConfig := range cfgs {
+		applyConfig(&configs)
+	}
+
+	var sentTokenizer *prose.PunktSentenceTokenizer
+	if configs.punkt != nil {
+		sentTokenizer = prose.NewCustomPunktSentenceTokenizer(configs.punkt.storage)
+	} else {
+		sentTokenizer = prose.NewPunktSentenceTokenizer()
+	}
+
+	model := prose.DefaultModel(configs.tagger == nil, configs.entities && configs.extracter == nil)
+	tagger := model.Tagger
+	if configs.tagger != nil {
+		tagger = configs.tagger.tagger
+	}
+
+	var extracter *prose.Model
+	if configs.extracter != nil {
+		extracter = configs.extracter.model
+	} else if configs.entities {
+		extracter = model
+	}
+
+	format := configs.format
+	if format == nil {
+		format = structure
+	}
+
 	return &Parser{
-		sentTokenizer: prose.NewPunktSentenceTokenizer(),
+		sentTokenizer: sentTokenizer,
 		wordTokenizer: prose.NewIterTokenizer(),
-		tagger:        prose.DefaultModel(true, false).Tagger,
+		tagger:        tagger,
+		extracter:     extracter,
 		analyzer:      govader.NewSentimentIntensityAnalyzer(),
+		format:        format,
 	}
 }
 
 // ParseDocument parses a document into sentences and tokens.
 // The result contains additional information such as sentence sentiment,
-// and POS-tags for tokens.
+// and POS-tags for tokens. The document's structure (paragraphs, headings and
+// list items) is recorded on each sentence.
 func (p *Parser) ParseDocument(doc string, quote bool) ([]*basically.Sentence, []*basically.Token, error) {
-	sents := p.sentTokenizer.Segment(doc)
-	retSents := make([]*basically.Sentence, 0, len(sents))
-	retTokens := make([]*basically.Token, 0, len(sents)*15)
+	units := p.format(doc)
+	retSents := make([]*basically.Sentence, 0, len(units))
+	retTokens := make([]*basically.Token, 0, len(doc)/5)
 
 	tokCounter := 0
-	for idx, sent := range sents {
-		tokens := p.wordTokenizer.Tokenize(sent.Text)
-		tokens = p.tagger.Tag(tokens)
-
-		// Convert struct from []*prose.Token to []*basically.Token.
-		btokens := make([]*basically.Token, 0, len(tokens))
-		for _, tok := range tokens {
-			btok := basically.Token{Tag: tok.Tag, Text: strings.ToLower(tok.Text), Order: tokCounter}
-			btokens = append(btokens, &btok)
-			tokCounter++
+	section := 0
+	for _, u := range units {
+		// Headings are never split into multiple sentences.
+		var texts []string
+		if u.kind == basically.Heading {
+			texts = []string{u.text}
+			if len(retSents) > 0 {
+				section++
+			}
+		} else {
+			for _, sent := range p.sentTokenizer.Segment(u.text) {
+				texts = append(texts, sent.Text)
+			}
 		}
 
-		// Analyzes sentence sentiment.
-		sentiment := p.analyzer.PolarityScores(sent.Text).Compound
+		pos := 0
+		for _, text := range texts {
+			idx := len(retSents)
+			tokens := p.wordTokenizer.Tokenize(text)
+			tokens = p.tagger.Tag(tokens)
 
-		retSents = append(retSents, &basically.Sentence{
-			Raw:       sent.Text,
-			Tokens:    btokens,
-			Sentiment: sentiment,
-			Bias:      1.0,
-			Order:     idx,
-		})
+			// Convert struct from []*prose.Token to []*basically.Token.
+			btokens := make([]*basically.Token, 0, len(tokens))
+			for _, tok := range tokens {
+				btok := basically.Token{Tag: tok.Tag, Text: strings.ToLower(tok.Text), Order: tokCounter, Sentence: idx}
+				btokens = append(btokens, &btok)
+				tokCounter++
+			}
+
+			// Extracts named-entities if enabled.
+			var bents []*basically.Entity
+			if p.extracter != nil {
+				bents = labelEntities(btokens, p.extracter.Extract(tokens))
+			}
+
+			// Analyzes sentence sentiment.
+			sentiment := p.analyzer.PolarityScores(text).Compound
 
-		retTokens = append(retTokens, btokens...)
+			// Locates the sentence within the unit, to find its offsets in the document.
+			if i := strings.Index(u.text[pos:], text); i >= 0 {
+				pos += i
+			}
+			start, end := u.span(pos, pos+len(text))
+			from, to := u.times(pos, pos+len(text))
+			pos += len(text)
+
+			retSents = append(retSents, &basically.Sentence{
+				Raw:       text,
+				Tokens:    btokens,
+				Entities:  bents,
+				Sentiment: sentiment,
+				Bias:      1.0,
+				Order:     idx,
+				Paragraph: u.paragraph,
+				Section:   section,
+				Kind:      u.kind,
+				Level:     u.level,
+				Start:     start,
+				End:       end,
+				StartTime: from,
+				EndTime:   to,
+				Speaker:   u.speaker,
+				Date:      u.date,
+				Message:   u.message,
+			})
+
+			retTokens = append(retTokens, btokens...)
+		}
 	}
 
 	return retSents, retTokens, nil
 }
+
+// labelEntities assigns IOB labels and entity types to the tokens, and converts
+// the named-entities from []prose.Entity to []*basically.Entity.
+func labelEntities(tokens []*basically.Token, ents []prose.Entity) []*basically.Entity {
+	for _, tok := range tokens {
+		tok.Label = "O"
+	}
+
+	bents := make([]*basically.Entity, 0, len(ents))
+	for _, ent := range ents {
+		for idx, tok := range tokens[ent.Start:ent.End] {
+			if idx == 0 {
+				tok.Label = "B-" + ent.Label
+			} else {
+				tok.Label = "I-" + ent.Label
+			}
+			tok.Entity = ent.Label
+		}
+		bents = append(bents, &basically.Entity{
+			Text:   ent.Text,
+			Label:  ent.Label,
+			Tokens: tokens[ent.Start:ent.End],
+		})
+	}
+
+	return bents
+}
@@ -0,0 +1,66 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/algao1/basically"
+	"github.com/algao1/basically/internal/prose"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLabelEntities(t *testing.T) {
+	toks := make([]*basically.Token, 0)
+	for _, word := range strings.Fields("angela merkel visited paris today") {
+		toks = append(toks, &basically.Token{Text: word})
+	}
+
+	ents := labelEntities(toks, []prose.Entity{
+		{Text: "Angela Merkel", Label: "PERSON", Start: 0, End: 2},
+		{Text: "Paris", Label: "GPE", Start: 3, End: 4},
+	})
+
+	labels := make([]string, 0, len(toks))
+	types := make([]string, 0, len(toks))
+	for _, tok := range toks {
+		labels = append(labels, tok.Label)
+		types = append(types, tok.Entity)
+	}
+	assert.Equal(t, []string{"B-PERSON", "I-PERSON", "O", "B-GPE", "O"}, labels)
+	assert.Equal(t, []string{"PERSON", "PERSON", "", "GPE", ""}, types)
+	assert.Equal(t, []*basically.Entity{
+		{Text: "Angela Merkel", Label: "PERSON", Tokens: toks[0:2]},
+		{Text: "Paris", Label: "GPE", Tokens: toks[3:4]},
+	}, ents)
+}
+
+func TestParseEntities(t *testing.T) {
+	p := Create(WithEntities())
+	sents, toks, err := p.ParseDocument("Angela Merkel met Emmanuel Macron in Paris on Tuesday.", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Every token is labelled, and entities agree with the labels of their tokens.
+	for _, tok := range toks {
+		assert.NotEmpty(t, tok.Label, tok.Text)
+	}
+	assert.NotEmpty(t, sents[0].Entities)
+	for _, ent := range sents[0].Entities {
+		for idx, tok := range ent.Tokens {
+			prefix := "I-"
+			if idx == 0 {
+				prefix = "B-"
+			}
+			assert.Equal(t, prefix+ent.Label, tok.Label, ent.Text)
+			assert.Equal(t, ent.Label, tok.Entity, ent.Text)
+		}
+	}
+
+	// Entities are not extracted unless enabled.
+	sents, _, err = Create().ParseDocument("Angela Merkel met Emmanuel Macron in Paris.", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Empty(t, sents[0].Entities)
+}
@@ -0,0 +1,65 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/algao1/basically/internal/prose"
+	"gopkg.in/neurosnap/sentences.v1"
+)
+
+// A PunktModel holds the parameters used by the Punkt sentence segmenter,
+// such as known abbreviations and frequent sentence starters.
+type PunktModel struct {
+	storage *sentences.Storage
+}
+
+// TrainPunkt learns Punkt parameters from a plain-text corpus, and merges
+// them with the default English model. Documents within the corpus are
+// treated as separate paragraphs.
+func TrainPunkt(corpus ...string) (*PunktModel, error) {
+	base, err := prose.DefaultPunktStorage()
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to load default punkt model", err)
+	}
+
+	trainer := prose.NewPunktTrainer(base)
+	for _, text := range corpus {
+		trainer.Train(text + "\n\n")
+	}
+
+	return &PunktModel{storage: trainer.Finalize()}, nil
+}
+
+// PunktFromDisk loads a PunktModel from the user-provided location.
+func PunktFromDisk(path string) (*PunktModel, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to read punkt model", err)
+	}
+
+	storage, err := sentences.LoadTraining(data)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to decode punkt model", err)
+	}
+
+	return &PunktModel{storage: storage}, nil
+}
+
+// Write saves a PunktModel to the user-provided location.
+func (m *PunktModel) Write(path string) error {
+	data, err := json.Marshal(m.storage)
+	if err != nil {
+		return fmt.Errorf("%q: %w", "unable to encode punkt model", err)
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// Abbreviations returns the abbreviations known to the model, in sorted order.
+func (m *PunktModel) Abbreviations() []string {
+	abbrevs := m.storage.AbbrevTypes.Array()
+	sort.Strings(abbrevs)
+	return abbrevs
+}
@@ -0,0 +1,270 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/algao1/basically"
+)
+
+var (
+	reBullet    = regexp.MustCompile(`^[ \t]*(?:[-*+•‣◦▪]|\(?\d{1,3}[.)])[ \t]+`)
+	reLetter    = regexp.MustCompile(`^[ \t]*(\(?)([a-zA-Z])([.)])[ \t]+`)
+	reSymbol    = regexp.MustCompile(`^[ \t]*[-*+•‣◦▪][ \t]+`)
+	reUnderline = regexp.MustCompile(`^[ \t]*(?:=+|-+)[ \t]*$`)
+)
+
+// The maximum number of words in a heading.
+const headingWords = 12
+
+// A unit is a piece of text with a single structural role, such as a heading,
+// a list item, or the text of a paragraph.
+type unit struct {
+	text      string
+	offsets   []int // The offset of each byte of text in the original document.
+	kind      basically.Kind
+	level     int // The heading level, if the unit is a heading.
+	paragraph int
+	cues      []cue     // The timed cues making up the text (if any).
+	speaker   string    // The speaker of the text (in transcripts), or its sender (in emails).
+	date      time.Time // The date of the text (in emails).
+	message   int       // The order of the message containing the text (in emails).
+}
+
+// A cue is a timed span of a unit's text, such as a subtitle.
+type cue struct {
+	start, end int           // The cue's span within the text.
+	from, to   time.Duration // The cue's start and end times.
+}
+
+// times returns the start time of the first cue, and the end time of the last cue,
+// overlapping text[start:end].
+func (u *unit) times(start, end int) (time.Duration, time.Duration) {
+	var from, to time.Duration
+	found := false
+	for _, c := range u.cues {
+		if c.end <= start || c.start >= end {
+			continue
+		}
+		if !found {
+			from, found = c.from, true
+		}
+		to = c.to
+	}
+	return from, to
+}
+
+// span returns the offsets in the original document of text[start:end].
+func (u *unit) span(start, end int) (int, int) {
+	if end > len(u.text) {
+		end = len(u.text)
+	}
+	if start >= end {
+		start = end - 1
+	}
+	return u.offsets[start], u.offsets[end-1] + 1
+}
+
+// A builder builds the text of a unit, while keeping track of the offsets
+// of each byte in the original document.
+type builder struct {
+	sb      strings.Builder
+	offsets []int
+	cues    []cue
+}
+
+// write appends text which starts at the given offset in the original document.
+func (b *builder) write(text string, start int) {
+	b.sb.WriteString(text)
+	for i := 0; i < len(text); i++ {
+		b.offsets = append(b.offsets, start+i)
+	}
+}
+
+// insert appends text which is not part of the original document (such as a separator),
+// mapping it to the given offset.
+func (b *builder) insert(text string, at int) {
+	b.sb.WriteString(text)
+	for len(b.offsets) < b.sb.Len() {
+		b.offsets = append(b.offsets, at)
+	}
+}
+
+// unit returns the built unit, trimmed of surrounding whitespace.
+func (b *builder) unit(kind basically.Kind, level, paragraph int) (unit, bool) {
+	text := b.sb.String()
+	start := len(text) - len(strings.TrimLeftFunc(text, unicode.IsSpace))
+	end := len(strings.TrimRightFunc(text, unicode.IsSpace))
+	if start >= end {
+		return unit{}, false
+	}
+
+	// Shift the cues to match the trimmed text.
+	cues := make([]cue, 0, len(b.cues))
+	for _, c := range b.cues {
+		c.start, c.end = c.start-start, c.end-start
+		cues = append(cues, c)
+	}
+
+	return unit{
+		text:      text[start:end],
+		offsets:   b.offsets[start:end],
+		kind:      kind,
+		level:     level,
+		paragraph: paragraph,
+		cues:      cues,
+	}, true
+}
+
+// A line is a line of the document, along with its offset.
+type line struct {
+	text  string
+	start int
+}
+
+// lines splits the document into lines, without line endings.
+func lines(doc string) []line {
+	var ret []line
+	for start := 0; start <= len(doc); {
+		end := strings.IndexByte(doc[start:], '\n')
+		if end < 0 {
+			end = len(doc) - start
+		}
+		ret = append(ret, line{text: strings.TrimRight(doc[start:start+end], "\r"), start: start})
+		start += end + 1
+	}
+	return ret
+}
+
+// blank determines if the line only contains whitespace.
+func (l line) blank() bool {
+	return strings.TrimSpace(l.text) == ""
+}
+
+// from returns the line from the given byte onwards.
+func (l line) from(idx int) line {
+	return line{text: l.text[idx:], start: l.start + idx}
+}
+
+// structure splits a plain-text document into units. Paragraphs are separated by blank lines.
+// Headings are short lines without terminal punctuation that form a paragraph of their own,
+// or lines underlined with "=" (level 1) or "-" (level 2). List items start with a bullet or
+// a number, and continue until the next item.
+func structure(doc string) []unit {
+	var units []unit
+	add := func(b *builder, kind basically.Kind, level, para int) {
+		if u, ok := b.unit(kind, level, para); ok {
+			units = append(units, u)
+		}
+	}
+
+	para := 0
+	for _, block := range blocks(lines(doc)) {
+		switch {
+		case len(block) >= 2 && reUnderline.MatchString(block[1].text) && bullet(block, 0) == nil:
+			level := 1
+			if strings.Contains(block[1].text, "-") {
+				level = 2
+			}
+			b := &builder{}
+			b.write(block[0].text, block[0].start)
+			add(b, basically.Heading, level, para)
+			block = block[2:]
+		case len(block) == 1 && isHeading(block[0].text):
+			b := &builder{}
+			b.write(block[0].text, block[0].start)
+			add(b, basically.Heading, 1, para)
+			block = nil
+		}
+
+		var b *builder
+		kind := basically.Body
+		for i, l := range block {
+			if loc := bullet(block, i); loc != nil {
+				if b != nil {
+					add(b, kind, 0, para)
+				}
+				b, kind = &builder{}, basically.Bullet
+				b.write(l.text[loc[1]:], l.start+loc[1])
+				continue
+			}
+
+			if b == nil {
+				b = &builder{}
+			} else {
+				b.insert("\n", l.start)
+			}
+			b.write(l.text, l.start)
+		}
+		if b != nil {
+			add(b, kind, 0, para)
+		}
+
+		para++
+	}
+
+	return units
+}
+
+// blocks groups consecutive non-blank lines together.
+func blocks(ls []line) [][]line {
+	var ret [][]line
+	var cur []line
+	for _, l := range ls {
+		if l.blank() {
+			if len(cur) > 0 {
+				ret = append(ret, cur)
+			}
+			cur = nil
+			continue
+		}
+		cur = append(cur, l)
+	}
+	if len(cur) > 0 {
+		ret = append(ret, cur)
+	}
+	return ret
+}
+
+// bullet returns the span of the bullet starting the i-th line of the block, if any. Letter bullets
+// (e.g. "a)") need another item of the block with the previous or next letter in the same form, so
+// that lines starting with an initial (e.g. "I. M. Pei designed it.") are not list items.
+func bullet(block []line, i int) []int {
+	if loc := reBullet.FindStringIndex(block[i].text); loc != nil {
+		return loc
+	}
+
+	m := reLetter.FindStringSubmatch(block[i].text)
+	if m == nil {
+		return nil
+	}
+	for j, l := range block {
+		o := reLetter.FindStringSubmatch(l.text)
+		if j == i || o == nil || o[1] != m[1] || o[3] != m[3] {
+			continue
+		}
+		if d := int(o[2][0]) - int(m[2][0]); d == 1 || d == -1 {
+			return []int{0, len(m[0])}
+		}
+	}
+	return nil
+}
+
+// isHeading determines if a line, standing on its own, is a heading.
+func isHeading(text string) bool {
+	text = strings.TrimSpace(text)
+	if text == "" || reSymbol.MatchString(text) || len(strings.Fields(text)) > headingWords {
+		return false
+	}
+
+	// Closing quotes and brackets are skipped (e.g. "It's over.").
+	trimmed := strings.TrimRight(text, "\"'”’)]")
+	if trimmed == "" {
+		return false
+	}
+	last, _ := utf8.DecodeLastRuneInString(trimmed)
+	return !strings.ContainsRune(".!?;:,", last) && strings.IndexFunc(text, unicode.IsLetter) >= 0
+}
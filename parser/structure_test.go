@@ -0,0 +1,71 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/algao1/basically"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStructure(t *testing.T) {
+	doc := "Annual Report\n" +
+		"=============\n\n" +
+		"Sales grew in every region.\n\n" +
+		"\"It's over.\"\n\n" +
+		"Highlights\n\n" +
+		"- Revenue rose.\n" +
+		"- Costs fell,\n" +
+		"  as planned.\n\n" +
+		"a) First option.\n" +
+		"b) Second option.\n\n" +
+		"The museum was designed by the architect\n" +
+		"I. M. Pei in 1978.\n"
+
+	units := structure(doc)
+	kinds := make([]basically.Kind, 0, len(units))
+	for _, u := range units {
+		kinds = append(kinds, u.kind)
+	}
+
+	assert.Equal(t, []string{
+		"Annual Report",
+		"Sales grew in every region.",
+		"\"It's over.\"",
+		"Highlights",
+		"Revenue rose.",
+		"Costs fell,\n  as planned.",
+		"First option.",
+		"Second option.",
+		"The museum was designed by the architect\nI. M. Pei in 1978.",
+	}, texts(units))
+	assert.Equal(t, []basically.Kind{
+		basically.Heading, basically.Body, basically.Body, basically.Heading,
+		basically.Bullet, basically.Bullet, basically.Bullet, basically.Bullet, basically.Body,
+	}, kinds)
+
+	// Offsets map the text back to the document.
+	for _, u := range units {
+		start, end := u.span(0, len(u.text))
+		assert.Equal(t, u.text, doc[start:end])
+	}
+}
+
+func TestIsHeading(t *testing.T) {
+	cases := []struct {
+		text    string
+		heading bool
+	}{
+		{"Results and Discussion", true},
+		{"What comes next?", false},
+		{"\"It's over.\"", false},
+		{"(See the appendix.)", false},
+		{"“Why now?”", false},
+		{"The “Big” Picture", true},
+		{"- Revenue rose", false},
+		{"2021", false},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.heading, isHeading(c.text), c.text)
+	}
+}
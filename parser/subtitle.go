@@ -0,0 +1,123 @@
+package parser
+
+import (
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/algao1/basically"
+)
+
+var (
+	reTiming   = regexp.MustCompile(`^\s*((?:\d+:)?\d{1,2}:\d{2}[.,]\d{1,3})\s*-->\s*((?:\d+:)?\d{1,2}:\d{2}[.,]\d{1,3})`)
+	reCueTag   = regexp.MustCompile(`<[^>]*>|\{\\[^}]*\}`)
+	reDialogue = regexp.MustCompile(`^\s*-\s+`)
+)
+
+// The pause between cues that starts a new paragraph.
+const cuePause = 2 * time.Second
+
+// subtitles splits SRT or WebVTT subtitles into units, joining consecutive cues together so that
+// sentences spanning several cues are segmented correctly. Cue identifiers, headers, comments
+// and styling are skipped, and formatting tags are stripped. Speakers named by voice tags or
+// uppercase prefixes are recorded.
+func subtitles(doc string) []unit {
+	var units []unit
+	var b *builder
+	var last time.Duration
+	speaker, para := "", 0
+	flush := func() {
+		if b != nil {
+			if u, ok := b.unit(basically.Body, 0, para); ok {
+				u.speaker = speaker
+				units = append(units, u)
+				para++
+			}
+		}
+		b = nil
+	}
+
+	ls := lines(doc)
+	for i := 0; i < len(ls); i++ {
+		// Anything outside of a cue (e.g. identifiers, NOTE and STYLE blocks) is skipped.
+		m := reTiming.FindStringSubmatch(ls[i].text)
+		if m == nil {
+			continue
+		}
+		from, to := timestamp(m[1]), timestamp(m[2])
+
+		if b != nil && from-last > cuePause {
+			flush()
+		}
+
+		// A cue naming a different speaker starts a new unit.
+		skip := 0
+		if i+1 < len(ls) {
+			var name string
+			if name, skip = voice(ls[i+1].text); name != "" && !strings.EqualFold(name, speaker) {
+				flush()
+				speaker = name
+			}
+		}
+		if b == nil {
+			b = &builder{}
+		}
+
+		c := cue{start: -1, from: from, to: to}
+		for ; i+1 < len(ls) && !ls[i+1].blank(); i++ {
+			l := ls[i+1].from(skip)
+			skip = 0
+			if loc := reDialogue.FindStringIndex(l.text); loc != nil {
+				l = l.from(loc[1])
+			}
+
+			if b.sb.Len() > 0 {
+				b.insert(" ", l.start)
+			}
+			if c.start < 0 {
+				c.start = b.sb.Len()
+			}
+			cueText(b, l)
+		}
+
+		if c.start >= 0 {
+			c.end = b.sb.Len()
+			b.cues = append(b.cues, c)
+		}
+		last = to
+	}
+	flush()
+
+	return units
+}
+
+// cueText writes the line of a cue, stripping formatting tags and decoding entities.
+func cueText(b *builder, l line) {
+	last := 0
+	for _, loc := range append(reCueTag.FindAllStringIndex(l.text, -1), []int{len(l.text), len(l.text)}) {
+		base := last
+		for _, ent := range reEntity.FindAllStringIndex(l.text[base:loc[0]], -1) {
+			b.write(l.text[last:base+ent[0]], l.start+last)
+			b.insert(html.UnescapeString(l.text[base+ent[0]:base+ent[1]]), l.start+base+ent[0])
+			last = base + ent[1]
+		}
+		b.write(l.text[last:loc[0]], l.start+last)
+		last = loc[1]
+	}
+}
+
+// timestamp parses a timestamp of the form "hh:mm:ss,ttt" (SRT) or "[hh:]mm:ss.ttt" (WebVTT).
+func timestamp(ts string) time.Duration {
+	ts = strings.Replace(ts, ",", ".", 1)
+	parts := strings.Split(ts, ":")
+
+	var d time.Duration
+	for _, part := range parts[:len(parts)-1] {
+		n, _ := strconv.Atoi(part)
+		d = d*60 + time.Duration(n)
+	}
+	secs, _ := strconv.ParseFloat(parts[len(parts)-1], 64)
+	return d*time.Minute + time.Duration(secs*float64(time.Second)).Round(time.Millisecond)
+}
@@ -0,0 +1,69 @@
+package parser
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimestamp(t *testing.T) {
+	cases := []struct {
+		ts string
+		d  time.Duration
+	}{
+		{"00:00:01,500", 1500 * time.Millisecond},
+		{"01:02:03,004", time.Hour + 2*time.Minute + 3*time.Second + 4*time.Millisecond},
+		{"02:03.250", 2*time.Minute + 3250*time.Millisecond},
+		{"1:00:00.000", time.Hour},
+	}
+
+	for _, tc := range cases {
+		assert.Equal(t, tc.d, timestamp(tc.ts), tc.ts)
+	}
+}
+
+func TestSubtitles(t *testing.T) {
+	srt := "1\n" +
+		"00:00:01,000 --> 00:00:02,500\n" +
+		"The committee met <i>today</i>\n" +
+		"to discuss\n\n" +
+		"2\n" +
+		"00:00:02,600 --> 00:00:04,000\n" +
+		"the budget &amp; taxes.\n\n" +
+		"3\n" +
+		"00:00:10,000 --> 00:00:11,000\n" +
+		"- Look: it passed.\n"
+
+	units := subtitles(srt)
+	assert.Equal(t, []string{
+		"The committee met today to discuss the budget & taxes.",
+		"Look: it passed.",
+	}, texts(units))
+
+	// Cues are joined within a unit, and a pause starts a new paragraph.
+	assert.Equal(t, []cue{
+		{start: 0, end: 34, from: time.Second, to: 2500 * time.Millisecond},
+		{start: 35, end: 54, from: 2600 * time.Millisecond, to: 4 * time.Second},
+	}, units[0].cues)
+	assert.Equal(t, []int{0, 1}, []int{units[0].paragraph, units[1].paragraph})
+	// Only uppercase prefixes name speakers.
+	assert.Equal(t, "", units[1].speaker)
+
+	vtt := "WEBVTT\n\n" +
+		"NOTE This comment is skipped.\n\n" +
+		"00:01.000 --> 00:02.000\n" +
+		"<v.loud Alice>We shipped it.\n\n" +
+		"00:02.000 --> 00:03.000\n" +
+		"<v Alice>Finally.\n\n" +
+		"00:03.000 --> 00:04.000\n" +
+		"BOB: Congratulations.\n"
+
+	units = subtitles(vtt)
+	speakers := make([]string, 0, len(units))
+	for _, u := range units {
+		speakers = append(speakers, u.speaker)
+	}
+	assert.Equal(t, []string{"We shipped it. Finally.", "Congratulations."}, texts(units))
+	assert.Equal(t, []string{"Alice", "BOB"}, speakers)
+}
@@ -0,0 +1,105 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/algao1/basically/internal/prose"
+)
+
+// A TaggedSentence is a sentence annotated with part-of-speech tags,
+// used to train and evaluate a TaggerModel.
+type TaggedSentence struct {
+	Words []string // The sentence's words.
+	Tags  []string // The part-of-speech tag of each word.
+}
+
+// A TaggerModel is an averaged perceptron part-of-speech tagger.
+type TaggerModel struct {
+	tagger *prose.PerceptronTagger
+}
+
+// ReadTagged reads slash-tagged text (e.g. "Pierre/NNP Vinken/NNP ,/,"),
+// with one sentence per line, using the given tag separator.
+func ReadTagged(text, sep string) []TaggedSentence {
+	return fromTuples(prose.ReadTagged(text, sep))
+}
+
+// ReadCoNLL reads CoNLL-U or CoNLL-2000/2003 formatted text, with sentences
+// separated by blank lines.
+func ReadCoNLL(text string) []TaggedSentence {
+	return fromTuples(prose.ReadCoNLL(text))
+}
+
+// TrainTagger trains a new TaggerModel on the given sentences for the specified
+// number of iterations. Every word must be tagged, and may not be empty.
+func TrainTagger(sents []TaggedSentence, iters int) (*TaggerModel, error) {
+	tuples := make(prose.TupleSlice, 0, len(sents))
+	for idx, sent := range sents {
+		if len(sent.Words) != len(sent.Tags) {
+			return nil, fmt.Errorf("sentence %d has %d words but %d tags", idx, len(sent.Words), len(sent.Tags))
+		}
+		for i, word := range sent.Words {
+			if word == "" {
+				return nil, fmt.Errorf("sentence %d has an empty word at position %d", idx, i)
+			}
+		}
+		tuples = append(tuples, [][]string{sent.Words, sent.Tags})
+	}
+
+	return &TaggerModel{tagger: prose.TrainPerceptronTagger(tuples, iters)}, nil
+}
+
+// TaggerFromDisk loads a TaggerModel from the user-provided location.
+func TaggerFromDisk(path string) (*TaggerModel, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to find tagger model", err)
+	}
+
+	tagger, err := prose.TaggerFromDisk(path)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to load tagger model", err)
+	}
+	return &TaggerModel{tagger: tagger}, nil
+}
+
+// Write saves a TaggerModel to the user-provided location.
+func (m *TaggerModel) Write(path string) error {
+	if err := m.tagger.Write(path); err != nil {
+		return fmt.Errorf("%q: %w", "unable to write tagger model", err)
+	}
+	return nil
+}
+
+// Accuracy returns the fraction of words in the given sentences that are
+// correctly tagged by the model.
+func (m *TaggerModel) Accuracy(sents []TaggedSentence) float64 {
+	var correct, total float64
+	for _, sent := range sents {
+		tokens := make([]*prose.Token, 0, len(sent.Words))
+		for _, word := range sent.Words {
+			tokens = append(tokens, &prose.Token{Text: word})
+		}
+
+		for idx, tok := range m.tagger.Tag(tokens) {
+			if tok.Tag == sent.Tags[idx] {
+				correct++
+			}
+			total++
+		}
+	}
+
+	if total == 0 {
+		return 0
+	}
+	return correct / total
+}
+
+// fromTuples converts (words, tags) tuples into tagged sentences.
+func fromTuples(tuples prose.TupleSlice) []TaggedSentence {
+	sents := make([]TaggedSentence, 0, len(tuples))
+	for _, tuple := range tuples {
+		sents = append(sents, TaggedSentence{Words: tuple[0], Tags: tuple[1]})
+	}
+	return sents
+}
@@ -0,0 +1,21 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrainTagger(t *testing.T) {
+	sents := ReadTagged("The/DT dog/NN barks/VBZ ./.\nA/DT cat/NN sleeps/VBZ ./.", "/")
+	m, err := TrainTagger(sents, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 1.0, m.Accuracy(sents))
+
+	_, err = TrainTagger([]TaggedSentence{{Words: []string{"The", "dog"}, Tags: []string{"DT"}}}, 5)
+	assert.Error(t, err)
+	_, err = TrainTagger([]TaggedSentence{{Words: []string{"The", ""}, Tags: []string{"DT", "NN"}}}, 5)
+	assert.EqualError(t, err, "sentence 0 has an empty word at position 1")
+}
@@ -0,0 +1,82 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/algao1/basically"
+)
+
+var (
+	// Speakers are named by up to three capitalized words (the first starting with a letter),
+	// optionally followed by a parenthetical such as "(PM)", e.g. "Alice:", "Dr. Smith:" or
+	// "SPEAKER 2 (host):".
+	reSpeaker = regexp.MustCompile(`^[ \t]*((?:\p{Lu}[\p{L}\p{N}.'\-]*)(?:[ \t]+[\p{Lu}\d][\p{L}\p{N}.'\-]*){0,2})[ \t]*(?:\([^)]*\))?[ \t]*:[ \t]+`)
+	reVoice   = regexp.MustCompile(`^[ \t]*<v(?:\.[^ \t>]+)*[ \t]+([^>]+)>`)
+)
+
+// transcript splits a plain-text transcript into units, one for each turn (or paragraph
+// within a turn). Turns start with the name of their speaker, and continue until the next turn.
+// Names are only taken as speakers if they start more than one turn, so that labels such as
+// "Note:" are left in the text.
+func transcript(doc string) []unit {
+	ls := lines(doc)
+	turns := make(map[string]int)
+	for _, l := range ls {
+		if m := reSpeaker.FindStringSubmatch(l.text); m != nil {
+			turns[m[1]]++
+		}
+	}
+
+	var units []unit
+	var b *builder
+	speaker, para := "", 0
+	flush := func() {
+		if b != nil {
+			if u, ok := b.unit(basically.Body, 0, para); ok {
+				u.speaker = speaker
+				units = append(units, u)
+				para++
+			}
+		}
+		b = nil
+	}
+
+	for _, l := range ls {
+		if l.blank() {
+			flush()
+			continue
+		}
+
+		if m := reSpeaker.FindStringSubmatchIndex(l.text); m != nil && turns[l.text[m[2]:m[3]]] > 1 {
+			flush()
+			speaker = l.text[m[2]:m[3]]
+			l = l.from(m[1])
+		}
+
+		if b == nil {
+			b = &builder{}
+		} else {
+			b.insert("\n", l.start)
+		}
+		b.write(l.text, l.start)
+	}
+	flush()
+
+	return units
+}
+
+// voice returns the speaker of a cue, given as a WebVTT voice tag (e.g. "<v Alice>") or an
+// uppercase "SPEAKER:" prefix, along with the length of the prefix to strip (if any).
+func voice(text string) (string, int) {
+	if m := reVoice.FindStringSubmatch(text); m != nil {
+		return strings.TrimSpace(m[1]), 0
+	}
+	// Only uppercase names are recognized, to avoid mistaking phrases such as "Look: ..." for speakers.
+	if m := reSpeaker.FindStringSubmatchIndex(text); m != nil {
+		if name := text[m[2]:m[3]]; name == strings.ToUpper(name) && strings.ToLower(name) != name {
+			return name, m[1]
+		}
+	}
+	return "", 0
+}
@@ -0,0 +1,30 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTranscript(t *testing.T) {
+	doc := "Alice: We shipped the new billing service.\n" +
+		"Note: the rollout is staged.\n" +
+		"Bob (PM): Great news.\n" +
+		"Dr. Smith: The data looks fine.\n" +
+		"alice: Thanks.\n" +
+		"Alice: Staging is next.\n" +
+		"Bob (PM): Then production.\n"
+
+	units := transcript(doc)
+	speakers := make([]string, len(units))
+	for idx, u := range units {
+		speakers[idx] = u.speaker
+	}
+	assert.Equal(t, []string{
+		"We shipped the new billing service.\nNote: the rollout is staged.",
+		"Great news.\nDr. Smith: The data looks fine.\nalice: Thanks.",
+		"Staging is next.",
+		"Then production.",
+	}, texts(units))
+	assert.Equal(t, []string{"Alice", "Bob", "Alice", "Bob"}, speakers)
+}
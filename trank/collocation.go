@@ -0,0 +1,195 @@
+package trank
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/algao1/basically"
+)
+
+// An Association scores how strongly two units (words or phrases) are associated, given the
+// number of times each occurs (countA, countB), the number of times they occur together
+// (countAB), and the total number of words (n).
+type Association func(countA, countB, countAB, n float64) float64
+
+// PMI computes the pointwise mutual information of the two units. PMI favours rare
+// collocations, and is best used with a minimum count.
+func PMI(countA, countB, countAB, n float64) float64 {
+	return math.Log2(countAB * n / (countA * countB))
+}
+
+// LogLikelihood computes Dunning's log-likelihood ratio (G²) of the two units. Scores above
+// 10.83 are significant at p < 0.001.
+//
+// See Dunning (1993), "Accurate Methods for the Statistics of Surprise and Coincidence".
+func LogLikelihood(countA, countB, countAB, n float64) float64 {
+	// Observed frequencies of the 2x2 contingency table.
+	obs := [4]float64{countAB, countA - countAB, countB - countAB, n - countA - countB + countAB}
+	// Expected frequencies under independence.
+	exp := [4]float64{
+		countA * countB / n,
+		countA * (n - countB) / n,
+		(n - countA) * countB / n,
+		(n - countA) * (n - countB) / n,
+	}
+
+	var g2 float64
+	for i := range obs {
+		if obs[i] > 0 && exp[i] > 0 {
+			g2 += obs[i] * math.Log(obs[i]/exp[i])
+		}
+	}
+	return 2 * g2
+}
+
+// A Collocation is a sequence of words that co-occur more often than expected by chance.
+type Collocation struct {
+	Words []string // The collocation's (lowercased) words.
+	Count int      // The number of occurrences.
+	Score float64  // The association score of the words.
+}
+
+// A CollocationFinder finds bigram and trigram collocations in a sequence of tokens.
+// Collocations never cross sentence boundaries.
+type CollocationFinder struct {
+	Association Association           // Defaults to LogLikelihood.
+	Filter      basically.TokenFilter // Words allowed within collocations. Defaults to words starting with a letter.
+	MinCount    int                   // Collocations must occur at least twice, or MinCount times if greater.
+	MinScore    float64               // Collocations scoring lower are ignored.
+}
+
+// Find returns the collocations within the tokens, sorted by descending score.
+// Trigrams are scored as the weaker of their two splits, (w1 w2, w3) and (w1, w2 w3).
+func (cf *CollocationFinder) Find(tokens []*basically.Token) []Collocation {
+	assoc := cf.Association
+	if assoc == nil {
+		assoc = LogLikelihood
+	}
+	filter := cf.Filter
+	if filter == nil {
+		filter = alphaStart
+	}
+	minCount := cf.MinCount
+	if minCount < 2 {
+		minCount = 2
+	}
+
+	// Count the unigrams, bigrams and trigrams.
+	counts := make(map[string]int)
+	grams := make([]string, 0)
+	for i, tok := range tokens {
+		counts[tok.Text]++
+		for n := 2; n <= 3; n++ {
+			if !ngram(tokens[i:], n, filter) {
+				break
+			}
+			key := joinText(tokens[i : i+n])
+			if counts[key] == 0 {
+				grams = append(grams, key)
+			}
+			counts[key]++
+		}
+	}
+
+	total := float64(len(tokens))
+	score := func(a, b, ab string) float64 {
+		return assoc(float64(counts[a]), float64(counts[b]), float64(counts[ab]), total)
+	}
+
+	colls := make([]Collocation, 0)
+	for _, key := range grams {
+		if counts[key] < minCount {
+			continue
+		}
+
+		words := strings.Split(key, " ")
+		var s float64
+		if len(words) == 2 {
+			s = score(words[0], words[1], key)
+		} else {
+			s = math.Min(
+				score(words[0]+" "+words[1], words[2], key),
+				score(words[0], words[1]+" "+words[2], key),
+			)
+		}
+
+		if s >= cf.MinScore {
+			colls = append(colls, Collocation{Words: words, Count: counts[key], Score: s})
+		}
+	}
+
+	sort.Slice(colls, func(i, j int) bool {
+		if colls[i].Score != colls[j].Score {
+			return colls[i].Score > colls[j].Score
+		}
+		return strings.Join(colls[i].Words, " ") < strings.Join(colls[j].Words, " ")
+	})
+	return colls
+}
+
+// JoinCollocations joins the tokens of each occurrence of the collocations into a single token,
+// preferring longer collocations. Joined tokens take the tag of their last word.
+func JoinCollocations(tokens []*basically.Token, colls []Collocation) []*basically.Token {
+	known := make(map[string]struct{}, len(colls))
+	for _, coll := range colls {
+		known[strings.Join(coll.Words, " ")] = struct{}{}
+	}
+
+	joined := make([]*basically.Token, 0, len(tokens))
+	for i := 0; i < len(tokens); {
+		n := 1
+		for size := 3; size >= 2; size-- {
+			if !ngram(tokens[i:], size, nil) {
+				continue
+			}
+			if _, ok := known[joinText(tokens[i:i+size])]; ok {
+				n = size
+				break
+			}
+		}
+
+		if n == 1 {
+			joined = append(joined, tokens[i])
+		} else {
+			tok := *tokens[i]
+			tok.Text = joinText(tokens[i : i+n])
+			tok.Tag = tokens[i+n-1].Tag
+			joined = append(joined, &tok)
+		}
+		i += n
+	}
+
+	return joined
+}
+
+// ngram determines if the first n tokens form an n-gram within a single sentence,
+// with every token satisfying the filter (if any).
+func ngram(tokens []*basically.Token, n int, filter basically.TokenFilter) bool {
+	if len(tokens) < n {
+		return false
+	}
+	for _, tok := range tokens[:n] {
+		if tok.Sentence != tokens[0].Sentence || (filter != nil && !filter(tok)) {
+			return false
+		}
+	}
+	return true
+}
+
+// joinText joins the text of the tokens with spaces.
+func joinText(tokens []*basically.Token) string {
+	words := make([]string, 0, len(tokens))
+	for _, tok := range tokens {
+		words = append(words, tok.Text)
+	}
+	return strings.Join(words, " ")
+}
+
+// alphaStart determines if the token starts with a letter.
+func alphaStart(tok *basically.Token) bool {
+	r, _ := utf8.DecodeRuneInString(tok.Text)
+	return unicode.IsLetter(r)
+}
@@ -0,0 +1,114 @@
+package trank
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssociation(t *testing.T) {
+	cases := []struct {
+		name                    string
+		assoc                   Association
+		countA, countB, countAB float64
+		n                       float64
+		score                   float64
+	}{
+		{"PMI", PMI, 2, 4, 2, 16, 2},
+		{"PMI independent", PMI, 2, 2, 1, 4, 0},
+		{"PMI rare", PMI, 1, 1, 1, 4, 2},
+		{"LogLikelihood independent", LogLikelihood, 2, 2, 1, 4, 0},
+		// Observed {2, 0, 0, 2} against expected {1, 1, 1, 1}: 2 * (2 ln 2 + 2 ln 2).
+		{"LogLikelihood", LogLikelihood, 2, 2, 2, 4, 8 * math.Ln2},
+		// Observed {1, 0, 0, 1} against expected {0.5, 0.5, 0.5, 0.5}: 2 * (ln 2 + ln 2).
+		{"LogLikelihood pair", LogLikelihood, 1, 1, 1, 2, 4 * math.Ln2},
+	}
+
+	for _, tc := range cases {
+		assert.InDelta(t, tc.score, tc.assoc(tc.countA, tc.countB, tc.countAB, tc.n), 1e-9, tc.name)
+	}
+}
+
+func TestCollocationFinder(t *testing.T) {
+	toks := sentences(
+		"new/JJ york/NNP is/VBZ big/JJ",
+		"new/JJ york/NNP is/VBZ old/JJ",
+		"it/PRP is/VBZ cold/JJ",
+	)
+
+	// With 11 words, "new york" scores log2(2*11/(2*2)), and "york is" scores log2(2*11/(2*3)).
+	// The trigram takes the weaker of ("new york", "is") and ("new", "york is").
+	cases := []struct {
+		name  string
+		cf    *CollocationFinder
+		words []string
+		score []float64
+	}{
+		{
+			"PMI",
+			&CollocationFinder{Association: PMI},
+			[]string{"new york", "new york is", "york is"},
+			[]float64{math.Log2(5.5), math.Log2(11.0 / 3), math.Log2(11.0 / 3)},
+		},
+		{
+			"MinScore",
+			&CollocationFinder{Association: PMI, MinScore: 2},
+			[]string{"new york"},
+			[]float64{math.Log2(5.5)},
+		},
+		{
+			"MinCount",
+			&CollocationFinder{Association: PMI, MinCount: 3},
+			[]string{},
+			[]float64{},
+		},
+	}
+
+	for _, tc := range cases {
+		colls := tc.cf.Find(toks)
+		words := make([]string, 0, len(colls))
+		scores := make([]float64, 0, len(colls))
+		for _, coll := range colls {
+			assert.Equal(t, 2, coll.Count, tc.name)
+			words = append(words, strings.Join(coll.Words, " "))
+			scores = append(scores, coll.Score)
+		}
+		assert.Equal(t, tc.words, words, tc.name)
+		assert.InDeltaSlice(t, tc.score, scores, 1e-9, tc.name)
+	}
+
+	// Collocations never cross sentence boundaries.
+	cf := &CollocationFinder{}
+	assert.Empty(t, cf.Find(sentences("red/JJ", "wine/NN", "red/JJ", "wine/NN")))
+}
+
+func TestJoinCollocations(t *testing.T) {
+	newYork := Collocation{Words: []string{"new", "york"}}
+	newYorkIs := Collocation{Words: []string{"new", "york", "is"}}
+
+	cases := []struct {
+		name   string
+		colls  []Collocation
+		joined []string
+	}{
+		{"none", nil, []string{"new/JJ", "york/NNP", "is/VBZ", "big/JJ", "new/JJ", "york/NNP"}},
+		{"bigram", []Collocation{newYork}, []string{"new york/NNP", "is/VBZ", "big/JJ", "new york/NNP"}},
+		{"longest", []Collocation{newYork, newYorkIs}, []string{"new york is/VBZ", "big/JJ", "new york/NNP"}},
+	}
+
+	for _, tc := range cases {
+		toks := sentences("new/JJ york/NNP is/VBZ big/JJ new/JJ york/NNP")
+		joined := make([]string, 0)
+		for _, tok := range JoinCollocations(toks, tc.colls) {
+			joined = append(joined, tok.Text+"/"+tok.Tag)
+		}
+		assert.Equal(t, tc.joined, joined, tc.name)
+		assert.Equal(t, "new", toks[0].Text, tc.name)
+	}
+
+	// Collocations are not joined across sentences.
+	toks := JoinCollocations(sentences("big/JJ new/JJ", "york/NNP"), []Collocation{newYork})
+	assert.Len(t, toks, 3)
+}
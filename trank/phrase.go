@@ -0,0 +1,219 @@
+package trank
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/algao1/basically"
+)
+
+// A PhraseScoring computes the weight of a multi-word keyword from the weights of its words.
+type PhraseScoring func(weights []float64) float64
+
+// Sum scores a phrase by the total weight of its words, favouring longer phrases.
+func Sum(weights []float64) float64 {
+	var sum float64
+	for _, w := range weights {
+		sum += w
+	}
+	return sum
+}
+
+// Mean scores a phrase by the average weight of its words.
+func Mean(weights []float64) float64 {
+	return Sum(weights) / float64(len(weights))
+}
+
+// Max scores a phrase by the weight of its most significant word.
+func Max(weights []float64) float64 {
+	max := weights[0]
+	for _, w := range weights[1:] {
+		max = math.Max(max, w)
+	}
+	return max
+}
+
+// Normalized scores a phrase by the total weight of its words, divided by the square root
+// of its length. Longer phrases are favoured, but less so than with Sum.
+func Normalized(weights []float64) float64 {
+	return Sum(weights) / math.Sqrt(float64(len(weights)))
+}
+
+// A Pattern restricts multi-word keywords to sequences of part-of-speech tags.
+// Patterns are regular expressions over tags, such as "(JJ)*(NN)+", where each tag
+// also matches the tags it prefixes (e.g. "NN" matches "NN", "NNS", "NNP" and "NNPS").
+type Pattern struct {
+	re *regexp.Regexp
+}
+
+var reTag = regexp.MustCompile(`[A-Z][A-Z$]*`)
+
+// CompilePattern parses a part-of-speech pattern.
+func CompilePattern(expr string) (*Pattern, error) {
+	re, err := regexp.Compile(reTag.ReplaceAllString(expr, `<${0}[A-Z$$]*>`))
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to compile pattern", err)
+	}
+	return &Pattern{re: re}, nil
+}
+
+// MustCompilePattern is like CompilePattern, but panics if the pattern cannot be parsed.
+func MustCompilePattern(expr string) *Pattern {
+	p, err := CompilePattern(expr)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// spans returns the [start, end) token indices of the non-overlapping matches of the pattern.
+func (p *Pattern) spans(tokens []*basically.Token) [][2]int {
+	var sb strings.Builder
+	offsets := make(map[int]int, len(tokens)+1)
+	for idx, tok := range tokens {
+		offsets[sb.Len()] = idx
+		sb.WriteString("<" + tok.Tag + ">")
+	}
+	offsets[sb.Len()] = len(tokens)
+
+	var spans [][2]int
+	for _, loc := range p.re.FindAllStringIndex(sb.String(), -1) {
+		start, ok1 := offsets[loc[0]]
+		end, ok2 := offsets[loc[1]]
+		if ok1 && ok2 {
+			spans = append(spans, [2]int{start, end})
+		}
+	}
+	return spans
+}
+
+// phrases forms multi-word keywords from adjacent keywords within the same sentence,
+// that match the part-of-speech pattern (if any). The node sequence of each phrase is
+// recorded in `nodes`.
+func (kwtr *KWTextRank) phrases(top map[string]*basically.Keyword,
+	nodes map[*basically.Keyword][]string) []*basically.Keyword {
+	scoring := kwtr.Scoring
+	if scoring == nil {
+		scoring = Mean
+	}
+
+	keys := make([]string, 0)
+	seqs := make(map[string][]string)
+	forms := make(map[string]map[string]int)
+	add := func(run []*basically.Token) {
+		spans := [][2]int{{0, len(run)}}
+		if kwtr.Pattern != nil {
+			spans = kwtr.Pattern.spans(run)
+		}
+
+		for _, span := range spans {
+			if span[1]-span[0] < 2 {
+				continue
+			}
+
+			seq := make([]string, 0, span[1]-span[0])
+			words := make([]string, 0, span[1]-span[0])
+			for _, tok := range run[span[0]:span[1]] {
+				seq = append(seq, kwtr.node(tok))
+				words = append(words, tok.Text)
+			}
+
+			key := strings.Join(seq, " ")
+			if _, ok := seqs[key]; !ok {
+				keys = append(keys, key)
+				seqs[key] = seq
+				forms[key] = make(map[string]int)
+			}
+			forms[key][strings.Join(words, " ")]++
+		}
+	}
+
+	// Collect runs of keywords, which are broken at sentence boundaries.
+	var run []*basically.Token
+	for _, tok := range kwtr.Tokens {
+		_, ok := top[kwtr.node(tok)]
+		if ok && (len(run) == 0 || run[len(run)-1].Sentence == tok.Sentence) {
+			run = append(run, tok)
+			continue
+		}
+
+		add(run)
+		run = nil
+		if ok {
+			run = append(run, tok)
+		}
+	}
+	add(run)
+
+	phrases := make([]*basically.Keyword, 0, len(keys))
+	for _, key := range keys {
+		// Phrases are shown in their most frequent surface form.
+		var word string
+		var max int
+		for form, count := range forms[key] {
+			if count > max || (count == max && form < word) {
+				word, max = form, count
+			}
+		}
+
+		weights := make([]float64, 0, len(seqs[key]))
+		for _, node := range seqs[key] {
+			weights = append(weights, top[node].Weight)
+		}
+
+		kw := &basically.Keyword{Word: word, Weight: scoring(weights)}
+		nodes[kw] = seqs[key]
+		phrases = append(phrases, kw)
+	}
+
+	return phrases
+}
+
+// dedupe removes keywords that are subsumed by a longer, higher-ranked phrase. Keywords must be
+// sorted by rank, and any lower-ranked phrase replaces the keywords it subsumes.
+func dedupe(kwords []*basically.Keyword, nodes map[*basically.Keyword][]string) []*basically.Keyword {
+	kept := make([]*basically.Keyword, 0, len(kwords))
+	for _, kw := range kwords {
+		subsumed := false
+		for _, k := range kept {
+			if containsSeq(nodes[k], nodes[kw]) {
+				subsumed = true
+				break
+			}
+		}
+		if subsumed {
+			continue
+		}
+
+		remaining := kept[:0]
+		for _, k := range kept {
+			if !containsSeq(nodes[kw], nodes[k]) {
+				remaining = append(remaining, k)
+			}
+		}
+		kept = append(remaining, kw)
+	}
+
+	sort.SliceStable(kept, func(i, j int) bool { return rankBefore(kept[i], kept[j]) })
+	return kept
+}
+
+// containsSeq determines if sub is a contiguous subsequence of seq.
+func containsSeq(seq, sub []string) bool {
+	for i := 0; i+len(sub) <= len(seq); i++ {
+		match := true
+		for j := range sub {
+			if seq[i+j] != sub[j] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package trank
+
+import (
+	"testing"
+
+	"github.com/algao1/basically"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPhraseScoring(t *testing.T) {
+	weights := []float64{1, 2, 3, 4}
+	cases := []struct {
+		name    string
+		scoring PhraseScoring
+		score   float64
+	}{
+		{"Sum", Sum, 10},
+		{"Mean", Mean, 2.5},
+		{"Max", Max, 4},
+		{"Normalized", Normalized, 5},
+	}
+
+	for _, tc := range cases {
+		assert.InDelta(t, tc.score, tc.scoring(weights), 1e-9, tc.name)
+	}
+}
+
+func TestCompilePattern(t *testing.T) {
+	toks := sentences("big/JJ data/NN centres/NNS run/VB hot/JJR in/IN London/NNP")
+	cases := []struct {
+		expr  string
+		spans [][2]int
+	}{
+		{"(JJ)*(NN)+", [][2]int{{0, 3}, {6, 7}}},
+		{"(JJ)(NN)", [][2]int{{0, 2}}},
+		{"(NN)+", [][2]int{{1, 3}, {6, 7}}},
+		{"JJ", [][2]int{{0, 1}, {4, 5}}},
+		{"VB(JJ)?", [][2]int{{3, 5}}},
+	}
+
+	for _, tc := range cases {
+		p, err := CompilePattern(tc.expr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, tc.spans, p.spans(toks), tc.expr)
+	}
+
+	_, err := CompilePattern("(NN")
+	assert.Error(t, err)
+	assert.Panics(t, func() { MustCompilePattern("(NN") })
+}
+
+func TestDedupe(t *testing.T) {
+	kw := func(word string, weight float64) *basically.Keyword {
+		return &basically.Keyword{Word: word, Weight: weight}
+	}
+	nyc, council, york := kw("new york city", 3), kw("council", 2.5), kw("york", 2)
+	budget, cityCouncil, city := kw("budget", 1.5), kw("city council", 1), kw("city", 0.5)
+	nodes := map[*basically.Keyword][]string{
+		nyc:         {"new", "york", "city"},
+		council:     {"council"},
+		york:        {"york"},
+		budget:      {"budget"},
+		cityCouncil: {"city", "council"},
+		city:        {"city"},
+	}
+
+	// "york" and "city" are subsumed by "new york city", and "city council" replaces "council".
+	kept := dedupe([]*basically.Keyword{nyc, council, york, budget, cityCouncil, city}, nodes)
+	assert.Equal(t, []*basically.Keyword{nyc, budget, cityCouncil}, kept)
+
+	// Phrases are not subsumed by phrases they merely overlap.
+	newYork, yorkCity := kw("new york", 2), kw("york city", 1)
+	nodes[newYork], nodes[yorkCity] = []string{"new", "york"}, []string{"york", "city"}
+	kept = dedupe([]*basically.Keyword{newYork, yorkCity}, nodes)
+	assert.Equal(t, []*basically.Keyword{newYork, yorkCity}, kept)
+}
@@ -3,6 +3,7 @@ package trank
 import (
 	"math"
 	"sort"
+	"strings"
 
 	"github.com/algao1/basically"
 )
@@ -11,13 +12,67 @@ import (
 // a document. Edges represent the connection between words.
 type WGraph struct {
 	Nodes map[string]float64
-	Edges map[string]map[string]int
+	Edges map[string]map[string]float64
+	Forms map[string]map[string]int // The surface forms of each node, and their frequencies.
+}
+
+// Surface returns the most frequent surface form of the node, breaking ties alphabetically.
+func (g *WGraph) Surface(node string) string {
+	surface, max := node, 0
+	for form, count := range g.Forms[node] {
+		if count > max || (count == max && form < surface) {
+			surface, max = form, count
+		}
+	}
+	return surface
+}
+
+// A Weighting computes the weight of an edge between two words that co-occur
+// `dist` tokens apart, within a window of the given size.
+type Weighting func(dist, window int) float64
+
+// Uniform weighs every co-occurrence within the window equally.
+func Uniform(dist, window int) float64 {
+	return 1
+}
+
+// Inverse weighs co-occurrences inversely proportional to the distance between the words.
+func Inverse(dist, window int) float64 {
+	return 1 / float64(dist)
+}
+
+// Gaussian weighs co-occurrences by a Gaussian kernel centred on adjacent words,
+// with a standard deviation of half the window.
+func Gaussian(dist, window int) float64 {
+	sigma := math.Max(float64(window)/2, 1)
+	d := float64(dist - 1)
+	return math.Exp(-d * d / (2 * sigma * sigma))
 }
 
 // KWTextRank implements the Highlighter interface.
 type KWTextRank struct {
 	Graph  *WGraph
 	Tokens []*basically.Token
+	// Entities treats multi-token named-entities as single keyword candidates.
+	// Requires tokens to be labelled by the parser (see parser.WithEntities).
+	Entities bool
+	// Collocations joins the words of collocations (e.g. "public health england") into
+	// single keyword candidates before ranking.
+	Collocations *CollocationFinder
+	// Weighting computes the edge weights from the distance between words.
+	// Defaults to Inverse.
+	Weighting Weighting
+	// Bounded prevents co-occurrence windows from crossing sentence boundaries.
+	Bounded bool
+	// Normalize maps tokens to nodes, so that words sharing a lemma or stem are ranked as
+	// one keyword (e.g. sentence.Lemmatize or sentence.Stem). Keywords are shown in their
+	// most frequent surface form. Defaults to the token's text.
+	Normalize func(*basically.Token) string
+	// Scoring computes the weight of multi-word keywords. Defaults to Mean.
+	Scoring PhraseScoring
+	// Pattern restricts multi-word keywords to part-of-speech patterns (e.g. "(JJ)*(NN)+").
+	// Defaults to any sequence of keywords.
+	Pattern *Pattern
 }
 
 var _ basically.Highlighter = (*KWTextRank)(nil)
@@ -26,71 +81,152 @@ var _ basically.Highlighter = (*KWTextRank)(nil)
 func (kwtr *KWTextRank) Initialize(tokens []*basically.Token,
 	filter basically.TokenFilter, window int) {
 	// Instantiate a new WGraph.
-	kwtr.Graph = &WGraph{Nodes: make(map[string]float64), Edges: make(map[string]map[string]int)}
+	kwtr.Graph = &WGraph{
+		Nodes: make(map[string]float64),
+		Edges: make(map[string]map[string]float64),
+		Forms: make(map[string]map[string]int),
+	}
+	if kwtr.Entities {
+		tokens = mergeEntities(tokens)
+	}
+	if kwtr.Collocations != nil {
+		tokens = JoinCollocations(tokens, kwtr.Collocations.Find(tokens))
+	}
 	kwtr.Tokens = tokens
 
 	for i := 0; i < len(tokens); i++ {
 		// Insert tokens as nodes if they pass through filter.
 		if filter(tokens[i]) {
-			text := tokens[i].Text
-			kwtr.Graph.Nodes[text] = 1.0
-			if _, ok := kwtr.Graph.Edges[text]; !ok {
-				kwtr.Graph.Edges[text] = make(map[string]int)
+			node := kwtr.node(tokens[i])
+			kwtr.Graph.Nodes[node] = 1.0
+			if _, ok := kwtr.Graph.Edges[node]; !ok {
+				kwtr.Graph.Edges[node] = make(map[string]float64)
+				kwtr.Graph.Forms[node] = make(map[string]int)
 			}
+			kwtr.Graph.Forms[node][tokens[i].Text]++
 
 			// Backtracks to add edges.
-			if i >= window {
-				kwtr.lookBack(tokens[i-window:i+1], filter)
+			start := i - window
+			if start < 0 {
+				start = 0
 			}
+			kwtr.lookBack(tokens[start:i+1], filter, window)
+		}
+	}
+}
+
+// node returns the node representing the token in the WGraph.
+func (kwtr *KWTextRank) node(tok *basically.Token) string {
+	if kwtr.Normalize != nil {
+		return kwtr.Normalize(tok)
+	}
+	return tok.Text
+}
+
+// mergeEntities collapses the tokens of each multi-token named-entity into a
+// single proper noun token.
+func mergeEntities(tokens []*basically.Token) []*basically.Token {
+	merged := make([]*basically.Token, 0, len(tokens))
+	for _, tok := range tokens {
+		n := len(merged)
+		if n > 0 && strings.HasPrefix(tok.Label, "I-") && merged[n-1].Entity == tok.Entity {
+			prev := *merged[n-1]
+			prev.Text += " " + tok.Text
+			prev.Tag = "NNP"
+			merged[n-1] = &prev
+			continue
 		}
+		merged = append(merged, tok)
 	}
+	return merged
 }
 
 // lookBack adds weighted edges to the WGraph if any of the previous tokens within a window
-// satisfies the filter.
-func (kwtr *KWTextRank) lookBack(tokens []*basically.Token, filter basically.TokenFilter) {
+// satisfies the filter. Weights are accumulated across repeated co-occurrences.
+func (kwtr *KWTextRank) lookBack(tokens []*basically.Token, filter basically.TokenFilter, window int) {
+	weighting := kwtr.Weighting
+	if weighting == nil {
+		weighting = Inverse
+	}
+
 	end := len(tokens) - 1
 	t1 := tokens[end]
-	for idx, t2 := range tokens[:end] {
-		if filter(t2) {
-			lt1, lt2 := t1.Text, t2.Text
-			// The weight of the edge increases the closer the words are.
-			kwtr.Graph.Edges[lt1][lt2] = end / (end - idx)
-			kwtr.Graph.Edges[lt2][lt1] = end / (end - idx)
+	for idx := end - 1; idx >= 0; idx-- {
+		t2 := tokens[idx]
+		if kwtr.Bounded && t2.Sentence != t1.Sentence {
+			break
+		}
+
+		if !filter(t2) {
+			continue
+		}
+		lt1, lt2 := kwtr.node(t1), kwtr.node(t2)
+		if lt1 == lt2 {
+			continue
 		}
+
+		// The weight of the edge depends on how close the words are.
+		w := weighting(end-idx, window)
+		kwtr.Graph.Edges[lt1][lt2] += w
+		kwtr.Graph.Edges[lt2][lt1] += w
 	}
 }
 
 // Rank applies the TextRank algorithm on the WGraph for some specified iterations.
+// Nodes and edges are visited in a fixed order, and scores are updated synchronously,
+// so that the ranking is deterministic.
 func (kwtr *KWTextRank) Rank(iters int) {
-	outWeights := kwtr.outWeights()
+	nodes, neighbours := kwtr.adjacency()
+	outWeights := kwtr.outWeights(neighbours)
 
 	for iter := 0; iter < iters; iter++ {
-		for to, edges := range kwtr.Graph.Edges {
+		scores := make(map[string]float64, len(nodes))
+		for _, to := range nodes {
 			var sum float64
-			for from := range edges {
+			for _, from := range neighbours[to] {
 				// Ignore outWeights if 0 to avoid Inf or NaN.
-				if outWeights[to] == 0 {
+				if outWeights[from] == 0 {
 					continue
 				}
-				sum += float64(kwtr.Graph.Edges[to][from]) / outWeights[from] * kwtr.Graph.Nodes[from]
+				sum += kwtr.Graph.Edges[to][from] / outWeights[from] * kwtr.Graph.Nodes[from]
 			}
-			kwtr.Graph.Nodes[to] = 0.15 + 0.85*sum
+			scores[to] = 0.15 + 0.85*sum
+		}
+		kwtr.Graph.Nodes = scores
+	}
+}
+
+// adjacency returns the sorted nodes of the WGraph, along with the sorted neighbours of each node.
+func (kwtr *KWTextRank) adjacency() ([]string, map[string][]string) {
+	nodes := make([]string, 0, len(kwtr.Graph.Nodes))
+	for node := range kwtr.Graph.Nodes {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+
+	neighbours := make(map[string][]string, len(nodes))
+	for _, node := range nodes {
+		adj := make([]string, 0, len(kwtr.Graph.Edges[node]))
+		for from := range kwtr.Graph.Edges[node] {
+			adj = append(adj, from)
 		}
+		sort.Strings(adj)
+		neighbours[node] = adj
 	}
+
+	return nodes, neighbours
 }
 
 // outWeights calculates the weights of outgoing edges.
-func (kwtr *KWTextRank) outWeights() map[string]float64 {
-	n := len(kwtr.Graph.Nodes)
-	weights := make(map[string]float64, n)
+func (kwtr *KWTextRank) outWeights(neighbours map[string][]string) map[string]float64 {
+	weights := make(map[string]float64, len(neighbours))
 
 	// Iterate over the edge sets.
-	for t, e := range kwtr.Graph.Edges {
+	for t, adj := range neighbours {
 		var sum float64
 		// Iterate over the edges/weights in the edge set.
-		for _, w := range e {
-			sum += float64(w)
+		for _, u := range adj {
+			sum += kwtr.Graph.Edges[t][u]
 		}
 		weights[t] = sum
 	}
@@ -100,6 +236,7 @@ func (kwtr *KWTextRank) outWeights() map[string]float64 {
 
 // Highlight sorts the keywords by weight, and returns the most significant keywords.
 // Can optionally be specified to merge keywords together to get multi-word extraction.
+// Multi-word keywords are scored by Scoring, and replace the keywords they subsume.
 func (kwtr *KWTextRank) Highlight(words int, merge bool) ([]*basically.Keyword, error) {
 	// If the number of keywords is negative, or greater than the number of vertices,
 	// automatically set to be 1/3 of the nodes.
@@ -107,62 +244,40 @@ func (kwtr *KWTextRank) Highlight(words int, merge bool) ([]*basically.Keyword,
 		words = len(kwtr.Graph.Nodes) / 3
 	}
 
-	// Create a list of keywords.
+	// Create a list of keywords, shown in their most frequent surface form.
 	kwords := make([]*basically.Keyword, 0, len(kwtr.Graph.Nodes))
-	for kw, w := range kwtr.Graph.Nodes {
-		kwords = append(kwords, &basically.Keyword{Word: kw, Weight: w})
+	nodes := make(map[*basically.Keyword][]string, len(kwtr.Graph.Nodes))
+	for node, w := range kwtr.Graph.Nodes {
+		kw := &basically.Keyword{Word: kwtr.Graph.Surface(node), Weight: w}
+		kwords = append(kwords, kw)
+		nodes[kw] = []string{node}
 	}
 
-	sort.Slice(kwords, func(i, j int) bool { return kwords[i].Weight > kwords[j].Weight })
-	kwords = kwords[:words]
+	sort.Slice(kwords, func(i, j int) bool { return rankBefore(kwords[i], kwords[j]) })
 
-	// Form multi-word keywords if specified.
+	// Form multi-word keywords from the top keywords if specified. Subsumed keywords
+	// are replaced by lower-ranked ones.
 	if merge {
-		// Create a dictionary to avoid duplicates.
-		kwdict := make(map[string]*basically.Keyword)
-		for _, kw := range kwords {
-			kwdict[kw.Word] = kw
+		top := make(map[string]*basically.Keyword, words)
+		for _, kw := range kwords[:words] {
+			top[nodes[kw][0]] = kw
 		}
 
-		// Procedure is similar to maximal-munch.
-		queue := make([]*basically.Keyword, 0)
-		for _, tok := range kwtr.Tokens {
-			if kw, ok := kwdict[tok.Text]; ok {
-				queue = append(queue, kw)
-				continue
-			} else if len(queue) > 1 {
-				// Append the new keyword if non-duplicate.
-				nKW := mergeKW(queue)
-				if _, ok := kwdict[nKW.Word]; !ok {
-					kwdict[nKW.Word] = nKW
-					kwords = append(kwords, nKW)
-				}
-			}
-			queue = nil
-		}
+		kwords = append(kwords, kwtr.phrases(top, nodes)...)
+		sort.Slice(kwords, func(i, j int) bool { return rankBefore(kwords[i], kwords[j]) })
+		kwords = dedupe(kwords, nodes)
 	}
 
-	sort.Slice(kwords, func(i, j int) bool { return kwords[i].Weight > kwords[j].Weight })
+	if words > len(kwords) {
+		words = len(kwords)
+	}
 	return kwords[:words], nil
 }
 
-// mergeKW merges a slice of keywords to form one keyword.
-func mergeKW(kws []*basically.Keyword) *basically.Keyword {
-	word := ""
-	max := 0.0
-	min := 0.01
-	sum := 0.0
-
-	for idx, kw := range kws {
-		if idx > 0 {
-			word += " "
-		}
-		word += kw.Word
-		max = math.Max(max, kw.Weight)
-		min = math.Min(min, kw.Weight)
-		sum += kw.Weight
+// rankBefore orders keywords by descending weight, breaking ties alphabetically.
+func rankBefore(k1, k2 *basically.Keyword) bool {
+	if k1.Weight != k2.Weight {
+		return k1.Weight > k2.Weight
 	}
-
-	weight := max - math.Abs(math.Log10(max))
-	return &basically.Keyword{Word: word, Weight: weight}
+	return k1.Word < k2.Word
 }
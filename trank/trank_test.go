@@ -0,0 +1,105 @@
+package trank
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/algao1/basically"
+	"github.com/stretchr/testify/assert"
+)
+
+// sentences creates tokens from space-separated word/TAG pairs, one sentence per string.
+func sentences(sents ...string) []*basically.Token {
+	var toks []*basically.Token
+	for idx, sent := range sents {
+		for _, pair := range strings.Fields(sent) {
+			i := strings.LastIndex(pair, "/")
+			toks = append(toks, &basically.Token{Text: pair[:i], Tag: pair[i+1:], Order: len(toks), Sentence: idx})
+		}
+	}
+	return toks
+}
+
+func TestWeighting(t *testing.T) {
+	cases := []struct {
+		name         string
+		weighting    Weighting
+		dist, window int
+		weight       float64
+	}{
+		{"Uniform", Uniform, 3, 4, 1},
+		{"Inverse adjacent", Inverse, 1, 4, 1},
+		{"Inverse", Inverse, 2, 4, 0.5},
+		{"Gaussian adjacent", Gaussian, 1, 4, 1},
+		// With a window of 4, sigma is 2 and the words are 2 tokens past adjacent.
+		{"Gaussian", Gaussian, 3, 4, math.Exp(-0.5)},
+		// Sigma is at least 1.
+		{"Gaussian small window", Gaussian, 2, 1, math.Exp(-0.5)},
+	}
+
+	for _, tc := range cases {
+		assert.InDelta(t, tc.weight, tc.weighting(tc.dist, tc.window), 1e-9, tc.name)
+	}
+}
+
+func TestEdges(t *testing.T) {
+	// Edge weights are listed for the word pairs "red apple", "red pie", "apple pie", "red car" and "pie car".
+	cases := []struct {
+		name      string
+		weighting Weighting
+		bounded   bool
+		window    int
+		edges     []float64
+	}{
+		// Co-occurrences 2 apart weigh 0.5, and repeated ones accumulate.
+		{"Inverse", nil, false, 2, []float64{1.5, 1.5, 1, 1, 0.5}},
+		{"Uniform", Uniform, false, 2, []float64{2, 2, 1, 1, 1}},
+		// The second "red" is not linked with the previous sentence.
+		{"Bounded", nil, true, 2, []float64{1, 0.5, 1, 1, 0}},
+		{"Window", nil, false, 1, []float64{1, 1, 1, 1, 0}},
+	}
+
+	pairs := [][2]string{{"red", "apple"}, {"red", "pie"}, {"apple", "pie"}, {"red", "car"}, {"pie", "car"}}
+	for _, tc := range cases {
+		kwtr := &KWTextRank{Weighting: tc.weighting, Bounded: tc.bounded}
+		kwtr.Initialize(sentences("red/JJ apple/NN pie/NN", "red/JJ car/NN"),
+			func(*basically.Token) bool { return true }, tc.window)
+
+		edges := make([]float64, 0, len(pairs))
+		for _, pair := range pairs {
+			assert.Equal(t, kwtr.Graph.Edges[pair[0]][pair[1]], kwtr.Graph.Edges[pair[1]][pair[0]], tc.name)
+			edges = append(edges, kwtr.Graph.Edges[pair[0]][pair[1]])
+		}
+		assert.InDeltaSlice(t, tc.edges, edges, 1e-9, tc.name)
+		assert.Zero(t, kwtr.Graph.Edges["red"]["red"], tc.name)
+	}
+}
+
+func TestMergeEntities(t *testing.T) {
+	// tagged creates tokens from space-separated word/TAG/LABEL triples.
+	tagged := func(triples string) []*basically.Token {
+		var toks []*basically.Token
+		for _, triple := range strings.Fields(triples) {
+			parts := strings.Split(triple, "/")
+			tok := &basically.Token{Text: parts[0], Tag: parts[1], Label: parts[2], Order: len(toks)}
+			if parts[2] != "O" {
+				tok.Entity = parts[2][2:]
+			}
+			toks = append(toks, tok)
+		}
+		return toks
+	}
+
+	toks := tagged("angela/NNP/B-PERSON merkel/NNP/I-PERSON met/VBD/O new/NNP/B-GPE york/NNP/I-GPE officials/NNS/O")
+	merged := mergeEntities(toks)
+	words := make([]string, 0, len(merged))
+	for _, tok := range merged {
+		words = append(words, tok.Text+"/"+tok.Tag)
+	}
+	assert.Equal(t, []string{"angela merkel/NNP", "met/VBD", "new york/NNP", "officials/NNS"}, words)
+	assert.Equal(t, "angela", toks[0].Text)
+
+	// Adjacent entities of different types are kept apart.
+	assert.Len(t, mergeEntities(tagged("paris/NNP/B-GPE google/NNP/I-ORG")), 2)
+}